@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/vibridi/gomock/v3/writer"
+)
+
+var errStale = errors.New("mocks are not up to date")
+
+// Regenerates in memory every mock file matched by patterns and prints a unified diff for each file
+// whose content on disk differs. Returns errStale if any file differs.
+func check(patterns []string) error {
+	files, err := goFiles(patterns)
+	if err != nil {
+		return err
+	}
+
+	stale := 0
+	for _, file := range files {
+		o, err := recordedOptions(file)
+		if err != nil {
+			return err
+		}
+		if o == nil {
+			continue
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		out, err := mock(o)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		want, err := writer.Content(file, "", src, out)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		d, err := diff(file, src, want)
+		if err != nil {
+			return err
+		}
+		if d != "" {
+			stale++
+			fmt.Print(d)
+		}
+	}
+
+	if stale > 0 {
+		return fmt.Errorf("%w: %d stale file(s)", errStale, stale)
+	}
+	return nil
+}
+
+// Returns the unified diff between the current and the wanted content of file, or an empty
+// string if they are equal.
+func diff(file string, current, want []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(string(want)),
+		FromFile: "a/" + file,
+		ToFile:   "b/" + file,
+		Context:  3,
+	})
+}
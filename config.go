@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configFiles lists the project config file names, in lookup order
+var configFiles = []string{"gomock.yaml", "gomock.yml", ".gomock.json"}
+
+var errNoConfig = errors.New("no config file found")
+
+// config is the content of a project config file. Each target describes one mock;
+// fields that a target leaves unset are taken from defaults.
+type config struct {
+	Defaults target   `yaml:"defaults" json:"defaults"`
+	Targets  []target `yaml:"targets" json:"targets"`
+}
+
+// target mirrors the command line options of a single mock generation.
+// Relative paths are resolved against the directory of the config file.
+type target struct {
+	Source        string            `yaml:"source" json:"source"`
+	Interface     string            `yaml:"interface" json:"interface"`
+	Destination   string            `yaml:"destination" json:"destination"`
+	Style         string            `yaml:"style" json:"style"` // either "options" or "struct"
+	Name          string            `yaml:"name" json:"name"`
+	Export        *bool             `yaml:"export" json:"export"`
+	Unnamed       *bool             `yaml:"unnamed" json:"unnamed"`
+	Disambiguate  *bool             `yaml:"disambiguate" json:"disambiguate"`
+	PrefixPackage *bool             `yaml:"prefix_package" json:"prefix_package"`
+	Local         *bool             `yaml:"local" json:"local"`
+	Strict        *bool             `yaml:"strict" json:"strict"`
+	Delegate      *bool             `yaml:"delegate" json:"delegate"`
+	Canned        *bool             `yaml:"canned_returns" json:"canned_returns"`
+	Sequenced     *bool             `yaml:"sequenced_returns" json:"sequenced_returns"`
+	Record        *bool             `yaml:"record" json:"record"`
+	Expect        *bool             `yaml:"expect" json:"expect"`
+	Sequence      *bool             `yaml:"sequence" json:"sequence"`
+	Gates         *bool             `yaml:"gates" json:"gates"`
+	ContextAware  *bool             `yaml:"context_aware" json:"context_aware"`
+	Faults        *bool             `yaml:"faults" json:"faults"`
+	Verify        *bool             `yaml:"verify_overrides" json:"verify_overrides"`
+	Trace         *bool             `yaml:"trace" json:"trace"`
+	Reset         *bool             `yaml:"reset" json:"reset"`
+	SafeDefaults  *bool             `yaml:"safe_defaults" json:"safe_defaults"`
+	DefaultError  string            `yaml:"default_error" json:"default_error"`
+	Pkgs          map[string]string `yaml:"pkgs" json:"pkgs"`
+	Utype         map[string]string `yaml:"utype" json:"utype"`
+	Methods       []string          `yaml:"methods" json:"methods"`
+	Exclude       []string          `yaml:"exclude_methods" json:"exclude_methods"`
+}
+
+// Generates all targets listed in the config file. If file is empty, the config file is looked up
+// in the current directory and its parents, up to the module root.
+func generateConfig(file string) error {
+	if file == "" {
+		var err error
+		if file, err = findConfig(); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := readConfig(file)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(file)
+
+	for i, t := range cfg.Targets {
+		o, err := t.merge(cfg.Defaults).options(dir)
+		if err != nil {
+			return fmt.Errorf("%s: target %d: %w", file, i, err)
+		}
+		if err := generate(o); err != nil {
+			return fmt.Errorf("%s: target %d: %w", file, i, err)
+		}
+	}
+	return nil
+}
+
+// Looks up the config file starting from the current directory. The lookup stops at the first
+// directory that contains a go.mod file.
+func findConfig() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range configFiles {
+			f := filepath.Join(dir, name)
+			if _, err := os.Stat(f); err == nil {
+				return f, nil
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", fmt.Errorf("%w: expected one of %s", errNoConfig, strings.Join(configFiles, ", "))
+}
+
+func readConfig(file string) (*config, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config{}
+	if filepath.Ext(file) == ".json" {
+		err = json.Unmarshal(b, cfg)
+	} else {
+		err = yaml.Unmarshal(b, cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", file, err)
+	}
+	return cfg, nil
+}
+
+// Returns a copy of t where the unset fields are taken from defaults.
+// Mappings are merged, with the target's entries taking precedence.
+func (t target) merge(defaults target) target {
+	if t.Source == "" {
+		t.Source = defaults.Source
+	}
+	if t.Interface == "" {
+		t.Interface = defaults.Interface
+	}
+	if t.Destination == "" {
+		t.Destination = defaults.Destination
+	}
+	if t.Style == "" {
+		t.Style = defaults.Style
+	}
+	if t.Name == "" {
+		t.Name = defaults.Name
+	}
+	if t.DefaultError == "" {
+		t.DefaultError = defaults.DefaultError
+	}
+	for _, b := range []struct{ v, d **bool }{
+		{&t.Export, &defaults.Export},
+		{&t.Unnamed, &defaults.Unnamed},
+		{&t.Disambiguate, &defaults.Disambiguate},
+		{&t.PrefixPackage, &defaults.PrefixPackage},
+		{&t.Local, &defaults.Local},
+		{&t.Strict, &defaults.Strict},
+		{&t.Delegate, &defaults.Delegate},
+		{&t.Canned, &defaults.Canned},
+		{&t.Sequenced, &defaults.Sequenced},
+		{&t.Record, &defaults.Record},
+		{&t.Expect, &defaults.Expect},
+		{&t.Sequence, &defaults.Sequence},
+		{&t.Gates, &defaults.Gates},
+		{&t.ContextAware, &defaults.ContextAware},
+		{&t.Faults, &defaults.Faults},
+		{&t.Verify, &defaults.Verify},
+		{&t.Trace, &defaults.Trace},
+		{&t.Reset, &defaults.Reset},
+		{&t.SafeDefaults, &defaults.SafeDefaults},
+	} {
+		if *b.v == nil {
+			*b.v = *b.d
+		}
+	}
+	t.Pkgs = mergeMaps(defaults.Pkgs, t.Pkgs)
+	t.Utype = mergeMaps(defaults.Utype, t.Utype)
+	if t.Methods == nil {
+		t.Methods = defaults.Methods
+	}
+	if t.Exclude == nil {
+		t.Exclude = defaults.Exclude
+	}
+	return t
+}
+
+// Converts the target to command line options, resolving relative paths against dir.
+func (t target) options(dir string) (*options, error) {
+	if t.Source == "" {
+		return nil, errors.New("missing source")
+	}
+
+	o := &options{
+		sourceFiles:   fileList{resolve(dir, t.Source)},
+		target:        t.Interface,
+		mockName:      t.Name,
+		export:        isSet(t.Export),
+		unnamedsig:    isSet(t.Unnamed),
+		disambiguate:  isSet(t.Disambiguate),
+		prefixPackage: isSet(t.PrefixPackage),
+		noQualify:     isSet(t.Local),
+		strict:        isSet(t.Strict),
+		delegate:      isSet(t.Delegate),
+		canned:        isSet(t.Canned),
+		sequenced:     isSet(t.Sequenced),
+		record:        isSet(t.Record),
+		expect:        isSet(t.Expect),
+		sequence:      isSet(t.Sequence),
+		gates:         isSet(t.Gates),
+		contextAware:  isSet(t.ContextAware),
+		faults:        isSet(t.Faults),
+		verify:        isSet(t.Verify),
+		trace:         isSet(t.Trace),
+		reset:         isSet(t.Reset),
+		safeDefaults:  isSet(t.SafeDefaults),
+		defaultError:  t.DefaultError,
+		underlying:    *cli.NewStringSlice(mappings(t.Utype)...),
+		aliases:       *cli.NewStringSlice(mappings(t.Pkgs)...),
+		methods:       *cli.NewStringSlice(t.Methods...),
+		excluded:      *cli.NewStringSlice(t.Exclude...),
+	}
+	if t.Destination != "" {
+		o.destination = resolve(dir, t.Destination)
+	}
+
+	switch t.Style {
+	case "", "options":
+	case "struct":
+		o.structStyle = true
+	default:
+		return nil, fmt.Errorf("invalid style: %s", t.Style)
+	}
+	return o, nil
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+func isSet(b *bool) bool {
+	return b != nil && *b
+}
+
+func mergeMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 {
+		return override
+	}
+	m := make(map[string]string, len(base)+len(override))
+	maps.Copy(m, base)
+	maps.Copy(m, override)
+	return m
+}
+
+// Formats the map entries as 'key=value' mappings, sorted by key.
+func mappings(m map[string]string) []string {
+	ss := make([]string, 0, len(m))
+	for k, v := range m {
+		ss = append(ss, k+"="+v)
+	}
+	slices.Sort(ss)
+	return ss
+}
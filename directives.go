@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vibridi/gomock/v3/parser"
+)
+
+const (
+	layoutBeside = "beside"
+	layoutMocks  = "mocks"
+)
+
+// Generates every interface annotated with a //gomock:generate directive in the files matched by patterns.
+// The directive arguments are parsed as command line options; the output file is relative to the annotated source.
+// If base.all is set, the other exported interfaces are generated too, with the options in base
+// and an output file chosen according to base.layout.
+func generatePackages(patterns []string, base *options) error {
+	if base.all && base.layout != layoutBeside && base.layout != layoutMocks {
+		return fmt.Errorf("invalid layout: %s", base.layout)
+	}
+
+	files, err := goFiles(patterns)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		directives, err := parser.FindDirectives(file, nil)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		annotated := make(map[string]bool, len(directives))
+
+		for _, d := range directives {
+			annotated[d.InterfaceName] = true
+
+			o, err := parseArgs(d.Args)
+			if err != nil {
+				return fmt.Errorf("%s: %s: invalid directive: %w", file, d.InterfaceName, err)
+			}
+			o.sourceFiles = fileList{file}
+			o.target = d.InterfaceName
+			if o.destination != "" {
+				o.destination = resolve(filepath.Dir(file), o.destination)
+			}
+			if err := generate(o); err != nil {
+				return fmt.Errorf("%s: %s: %w", file, d.InterfaceName, err)
+			}
+		}
+
+		if !base.all || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		names, err := parser.FindExported(file, nil)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		for _, name := range names {
+			if annotated[name] {
+				continue
+			}
+			o, err := layoutOptions(base, file, name)
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", file, name, err)
+			}
+			if err := generate(o); err != nil {
+				return fmt.Errorf("%s: %s: %w", file, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns a copy of base that generates the interface name declared in file, with the output file given
+// by the layout. Beside the source, mocks are unqualified test files of the same package. In the mocks tree,
+// which mirrors the source tree from the current directory, mocks are exported to be usable from other packages;
+// they are declared in a package with the name of the source package, which they import.
+// The identifiers are always disambiguated, as a package may declare several interfaces with the same method names.
+func layoutOptions(base *options, file string, name string) (*options, error) {
+	o := *base
+	o.sourceFiles = fileList{file}
+	o.target = name
+	o.disambiguate = true
+
+	filename := "mock_" + strings.ToLower(name)
+	switch base.layout {
+	case layoutBeside:
+		o.noQualify = true
+		o.destination = filepath.Join(filepath.Dir(file), filename+"_test.go")
+
+	case layoutMocks:
+		o.export = true
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir, err := filepath.Abs(filepath.Dir(file))
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(wd, dir)
+		if err != nil {
+			return nil, err
+		}
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("source is outside the current directory, cannot mirror it in %s", layoutMocks)
+		}
+		o.destination = filepath.Join(layoutMocks, rel, filename+".go")
+		if o.pkg, err = packageName(file); err != nil {
+			return nil, err
+		}
+		if o.sourceImport, err = importPath(dir); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(o.destination), 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &o, nil
+}
+
+// Returns the name of the package declared in file.
+func packageName(file string) (string, error) {
+	f, err := goparser.ParseFile(token.NewFileSet(), file, nil, goparser.PackageClauseOnly)
+	if err != nil {
+		return "", err
+	}
+	return f.Name.Name, nil
+}
+
+// Returns the import path of the package in dir, given by the module path declared
+// in the go.mod file of dir or of its closest parent.
+func importPath(dir string) (string, error) {
+	for rel := "."; ; {
+		b, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			mod := modfileModule(b)
+			if mod == "" {
+				return "", fmt.Errorf("%s: missing module declaration", filepath.Join(dir, "go.mod"))
+			}
+			return path.Join(mod, filepath.ToSlash(rel)), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("source is not in a module, cannot import it")
+		}
+		rel = filepath.Join(filepath.Base(dir), rel)
+		dir = parent
+	}
+}
+
+// Returns the module path declared in the content of a go.mod file, or an empty string.
+func modfileModule(b []byte) string {
+	for line := range strings.Lines(string(b)) {
+		if f := strings.Fields(line); len(f) > 1 && f[0] == "module" {
+			if mod, err := strconv.Unquote(f[1]); err == nil {
+				return mod
+			}
+			return f[1]
+		}
+	}
+	return ""
+}
+
+// Reports whether arg is a directory or a pattern ending in "/...", as opposed to a source file.
+func isPattern(arg string) bool {
+	if strings.HasSuffix(arg, "/...") {
+		return true
+	}
+	fi, err := os.Stat(arg)
+	return err == nil && fi.IsDir()
+}
@@ -0,0 +1,187 @@
+// Package expect is the runtime support of the mocks generated by gomock with the --expect option.
+//
+// A generated mock holds a Controller, and its ExpectX methods add expected calls to it:
+//
+//	m := newMockStore(t)
+//	m.ExpectGet(expect.Eq("id")).Return(u, nil).Times(2)
+//
+// Arguments that aren't a Matcher are matched with Eq. When the test ends, the Controller fails it
+// if some expected calls didn't happen as many times as expected.
+//
+// With the --sequence option, mocks can also be attached to a shared Sequence, which verifies
+// the order of the calls across several mocks.
+package expect
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Controller holds the expected calls to a mock and verifies them when the test ends.
+// It is safe for concurrent use.
+type Controller struct {
+	t     testing.TB
+	mu    sync.Mutex
+	calls []*Call
+}
+
+// NewController returns a controller that verifies its expected calls in t.Cleanup.
+func NewController(t testing.TB) *Controller {
+	c := &Controller{t: t}
+	t.Cleanup(c.Verify)
+	return c
+}
+
+// Expect adds an expected call to method with the given arguments, once by default.
+func (c *Controller) Expect(method string, args ...any) *Call {
+	call := &Call{
+		ctrl:   c,
+		method: method,
+		args:   make([]Matcher, len(args)),
+		min:    1,
+		max:    1,
+	}
+	for i, a := range args {
+		call.args[i] = matcherOf(a)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, call)
+	return call
+}
+
+// Call matches an actual call to method against the expected calls, in the order they were added,
+// and returns the first one whose arguments match and that isn't exhausted. It returns nil if
+// the method has no expected calls. If none of them matches, the test is failed and Call returns nil.
+func (c *Controller) Call(method string, args ...any) *Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expected []*Call
+	for _, call := range c.calls {
+		if call.method != method {
+			continue
+		}
+		expected = append(expected, call)
+		if call.matches(args) && (call.max < 0 || call.calls < call.max) {
+			call.calls++
+			return call
+		}
+	}
+	if len(expected) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "unexpected call to %s(%s)\nexpected calls to %s:", method, formatArgs(args), method)
+	for _, call := range expected {
+		fmt.Fprintf(&b, "\n\t%s: %s", call, call.status())
+	}
+	c.t.Helper()
+	c.t.Errorf("%s", b.String())
+	return nil
+}
+
+// Verify fails the test if some expected calls didn't happen as many times as expected.
+// It is called automatically when the test ends.
+func (c *Controller) Verify() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.t.Helper()
+	for _, call := range c.calls {
+		if call.calls < call.min {
+			c.t.Errorf("missing call to %s: %s", call, call.status())
+		}
+	}
+}
+
+// Reset removes all expected calls, so that they are neither matched nor verified anymore.
+func (c *Controller) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = nil
+}
+
+// Call is an expected call to a mock method.
+type Call struct {
+	ctrl     *Controller
+	method   string
+	args     []Matcher
+	min, max int // max is negative when unlimited
+	calls    int
+	result   any
+}
+
+// Times sets how many times the call is expected.
+func (c *Call) Times(n int) *Call {
+	c.ctrl.mu.Lock()
+	defer c.ctrl.mu.Unlock()
+	c.min, c.max = n, n
+	return c
+}
+
+// AnyTimes allows the call to happen any number of times, including none.
+func (c *Call) AnyTimes() *Call {
+	c.ctrl.mu.Lock()
+	defer c.ctrl.mu.Unlock()
+	c.min, c.max = 0, -1
+	return c
+}
+
+// SetResult sets the values returned by the call. Generated mocks store them in a struct
+// specific to the method, and retrieve them with Result.
+func (c *Call) SetResult(v any) *Call {
+	c.ctrl.mu.Lock()
+	defer c.ctrl.mu.Unlock()
+	c.result = v
+	return c
+}
+
+// Result returns the values set with SetResult, or nil.
+func (c *Call) Result() any {
+	c.ctrl.mu.Lock()
+	defer c.ctrl.mu.Unlock()
+	return c.result
+}
+
+// String formats the call with its matchers, e.g. Get("id", any).
+func (c *Call) String() string {
+	args := make([]string, len(c.args))
+	for i, m := range c.args {
+		args[i] = m.String()
+	}
+	return c.method + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (c *Call) matches(args []any) bool {
+	if len(args) != len(c.args) {
+		return false
+	}
+	for i, m := range c.args {
+		if !m.Matches(args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Call) status() string {
+	switch {
+	case c.max < 0:
+		return fmt.Sprintf("called %d times", c.calls)
+	default:
+		return fmt.Sprintf("called %d of %d times", c.calls, c.max)
+	}
+}
+
+func formatArgs(args []any) string {
+	ss := make([]string, len(args))
+	for i, a := range args {
+		ss[i] = fmt.Sprintf("%#v", a)
+	}
+	return strings.Join(ss, ", ")
+}
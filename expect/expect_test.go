@@ -0,0 +1,112 @@
+package expect
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT collects the failures and cleanup functions of a test
+type fakeT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeT) end() {
+	for _, f := range t.cleanups {
+		f()
+	}
+}
+
+func TestController(t *testing.T) {
+	t.Run("no expectations", func(t *testing.T) {
+		ft := &fakeT{}
+		c := NewController(ft)
+		assert.Nil(t, c.Call("Get", "id"))
+		ft.end()
+		assert.Empty(t, ft.errors)
+	})
+
+	t.Run("matching call", func(t *testing.T) {
+		ft := &fakeT{}
+		c := NewController(ft)
+		want := c.Expect("Get", "id", Any()).SetResult(42)
+
+		call := c.Call("Get", "id", 3)
+		assert.Same(t, want, call)
+		assert.Equal(t, 42, call.Result())
+		ft.end()
+		assert.Empty(t, ft.errors)
+	})
+
+	t.Run("unexpected call", func(t *testing.T) {
+		ft := &fakeT{}
+		c := NewController(ft)
+		c.Expect("Get", "id")
+		c.Expect("Put", Any())
+
+		assert.Nil(t, c.Call("Get", "other"))
+		assert.Equal(t, []string{"unexpected call to Get(\"other\")\nexpected calls to Get:\n\tGet(\"id\"): called 0 of 1 times"}, ft.errors)
+	})
+
+	t.Run("times", func(t *testing.T) {
+		ft := &fakeT{}
+		c := NewController(ft)
+		c.Expect("Get", "id").Times(2)
+
+		assert.NotNil(t, c.Call("Get", "id"))
+		ft.end()
+		assert.Equal(t, []string{`missing call to Get("id"): called 1 of 2 times`}, ft.errors)
+
+		ft.errors = nil
+		assert.NotNil(t, c.Call("Get", "id"))
+		assert.Nil(t, c.Call("Get", "id"))
+		assert.Equal(t, []string{"unexpected call to Get(\"id\")\nexpected calls to Get:\n\tGet(\"id\"): called 2 of 2 times"}, ft.errors)
+	})
+
+	t.Run("any times", func(t *testing.T) {
+		ft := &fakeT{}
+		c := NewController(ft)
+		c.Expect("Get", "id").AnyTimes()
+		for range 3 {
+			assert.NotNil(t, c.Call("Get", "id"))
+		}
+		ft.end()
+		assert.Empty(t, ft.errors)
+	})
+
+	t.Run("first match wins", func(t *testing.T) {
+		ft := &fakeT{}
+		c := NewController(ft)
+		first := c.Expect("Get", Any())
+		second := c.Expect("Get", "id")
+
+		assert.Same(t, first, c.Call("Get", "id"))
+		assert.Same(t, second, c.Call("Get", "id"))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		ft := &fakeT{}
+		c := NewController(ft)
+		c.Expect("Get", "id")
+		c.Reset()
+
+		assert.Nil(t, c.Call("Get", "id"))
+		c.Expect("Put", "id")
+		assert.NotNil(t, c.Call("Put", "id"))
+		ft.end()
+		assert.Empty(t, ft.errors)
+	})
+}
@@ -0,0 +1,75 @@
+package expect
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Matcher matches an argument of an expected call.
+type Matcher interface {
+	// Matches reports whether x satisfies the matcher
+	Matches(x any) bool
+	// String describes the matcher in failure messages
+	String() string
+}
+
+type matcher struct {
+	desc  string
+	match func(x any) bool
+}
+
+func (m matcher) Matches(x any) bool {
+	return m.match(x)
+}
+
+func (m matcher) String() string {
+	return m.desc
+}
+
+// Any matches every argument.
+func Any() Matcher {
+	return matcher{"any", func(any) bool { return true }}
+}
+
+// Eq matches arguments deeply equal to v, as reported by reflect.DeepEqual.
+func Eq(v any) Matcher {
+	return matcher{fmt.Sprintf("%#v", v), func(x any) bool { return reflect.DeepEqual(v, x) }}
+}
+
+// Nil matches nil arguments, including typed nil pointers, maps, slices, channels and functions.
+func Nil() Matcher {
+	return matcher{"nil", isNil}
+}
+
+// Not matches the arguments that m doesn't match.
+func Not(m Matcher) Matcher {
+	return matcher{"not(" + m.String() + ")", func(x any) bool { return !m.Matches(x) }}
+}
+
+// Func matches the arguments of type T for which f returns true. desc describes the matcher in failure messages.
+func Func[T any](desc string, f func(T) bool) Matcher {
+	return matcher{desc, func(x any) bool {
+		v, ok := x.(T)
+		return ok && f(v)
+	}}
+}
+
+// Returns m if it's a Matcher, otherwise a matcher equal to m.
+func matcherOf(m any) Matcher {
+	if m, ok := m.(Matcher); ok {
+		return m
+	}
+	return Eq(m)
+}
+
+func isNil(x any) bool {
+	if x == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(x); v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
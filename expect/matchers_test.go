@@ -0,0 +1,36 @@
+package expect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchers(t *testing.T) {
+	var nilPtr *int
+	cases := []struct {
+		name    string
+		m       Matcher
+		desc    string
+		match   []any
+		nomatch []any
+	}{
+		{"any", Any(), "any", []any{nil, 1, "a"}, nil},
+		{"eq", Eq([]int{1}), "[]int{1}", []any{[]int{1}}, []any{[]int{2}, nil}},
+		{"nil", Nil(), "nil", []any{nil, nilPtr, []int(nil)}, []any{0, ""}},
+		{"not", Not(Eq("a")), `not("a")`, []any{"b"}, []any{"a"}},
+		{"func", Func("has prefix", func(s string) bool { return strings.HasPrefix(s, "a") }), "has prefix", []any{"ab"}, []any{"b", 1}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.desc, c.m.String())
+			for _, x := range c.match {
+				assert.True(t, c.m.Matches(x), x)
+			}
+			for _, x := range c.nomatch {
+				assert.False(t, c.m.Matches(x), x)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package expect
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Sequence records the calls to the mocks attached to it, in the order they happen, so that tests
+// can verify the order of calls across several mocks. Calls are identified as "Mock.Method",
+// e.g. "Tx.Commit". It is safe for concurrent use.
+type Sequence struct {
+	mu    sync.Mutex
+	calls []sequenceCall
+}
+
+type sequenceCall struct {
+	name string
+	args []any
+}
+
+func (c sequenceCall) String() string {
+	return c.name + "(" + formatArgs(c.args) + ")"
+}
+
+// NewSequence returns an empty sequence.
+func NewSequence() *Sequence {
+	return &Sequence{}
+}
+
+// Add records a call with its arguments. It does nothing on a nil sequence.
+func (s *Sequence) Add(name string, args ...any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, sequenceCall{name, args})
+}
+
+// Calls returns the names of the recorded calls, in order.
+func (s *Sequence) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.calls))
+	for i, c := range s.calls {
+		names[i] = c.name
+	}
+	return names
+}
+
+// AssertOrder fails the test unless the recorded calls are exactly calls, in the same order.
+func (s *Sequence) AssertOrder(t testing.TB, calls ...string) bool {
+	t.Helper()
+	if slices.Equal(s.Calls(), calls) {
+		return true
+	}
+	t.Errorf("%s", s.failure("calls don't match the expected order", calls))
+	return false
+}
+
+// AssertPartialOrder fails the test unless calls were recorded in the same order,
+// possibly interleaved with other calls.
+func (s *Sequence) AssertPartialOrder(t testing.TB, calls ...string) bool {
+	t.Helper()
+	i := 0
+	for _, name := range s.Calls() {
+		if i < len(calls) && name == calls[i] {
+			i++
+		}
+	}
+	if i == len(calls) {
+		return true
+	}
+	t.Errorf("%s", s.failure(fmt.Sprintf("calls don't match the expected partial order, missing %s", calls[i]), calls))
+	return false
+}
+
+// Formats a failure message with the expected calls and the actual interleaving.
+func (s *Sequence) failure(msg string, expected []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nexpected: %s\nactual:", msg, strings.Join(expected, ", "))
+	if len(s.calls) == 0 {
+		b.WriteString(" no calls")
+	}
+	for i, c := range s.calls {
+		fmt.Fprintf(&b, "\n\t%d. %s", i+1, c)
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package expect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequence(t *testing.T) {
+	seq := NewSequence()
+	seq.Add("Tx.Begin")
+	seq.Add("Repo.Save", "id", 1)
+	seq.Add("Tx.Commit")
+
+	t.Run("calls", func(t *testing.T) {
+		assert.Equal(t, []string{"Tx.Begin", "Repo.Save", "Tx.Commit"}, seq.Calls())
+	})
+
+	t.Run("nil sequence", func(t *testing.T) {
+		var s *Sequence
+		assert.NotPanics(t, func() { s.Add("Tx.Begin") })
+	})
+
+	t.Run("exact order", func(t *testing.T) {
+		ft := &fakeT{}
+		assert.True(t, seq.AssertOrder(ft, "Tx.Begin", "Repo.Save", "Tx.Commit"))
+		assert.Empty(t, ft.errors)
+
+		assert.False(t, seq.AssertOrder(ft, "Tx.Begin", "Tx.Commit"))
+		assert.Equal(t, []string{"calls don't match the expected order\n" +
+			"expected: Tx.Begin, Tx.Commit\n" +
+			"actual:\n" +
+			"\t1. Tx.Begin()\n" +
+			"\t2. Repo.Save(\"id\", 1)\n" +
+			"\t3. Tx.Commit()"}, ft.errors)
+	})
+
+	t.Run("partial order", func(t *testing.T) {
+		ft := &fakeT{}
+		assert.True(t, seq.AssertPartialOrder(ft, "Tx.Begin", "Tx.Commit"))
+		assert.True(t, seq.AssertPartialOrder(ft))
+		assert.Empty(t, ft.errors)
+
+		assert.False(t, seq.AssertPartialOrder(ft, "Tx.Commit", "Repo.Save"))
+		assert.Len(t, ft.errors, 1)
+		assert.Contains(t, ft.errors[0], "calls don't match the expected partial order, missing Repo.Save\n")
+	})
+
+	t.Run("no calls", func(t *testing.T) {
+		ft := &fakeT{}
+		assert.False(t, NewSequence().AssertOrder(ft, "Tx.Begin"))
+		assert.Equal(t, []string{"calls don't match the expected order\nexpected: Tx.Begin\nactual: no calls"}, ft.errors)
+	})
+}
@@ -0,0 +1,180 @@
+// Package fault is the runtime support of the mocks generated by gomock with the --faults option.
+//
+// An Injector holds rules that add latency, return errors or panic when the methods of a mock are called:
+//
+//	inj := fault.New(42)
+//	inj.On("Get").Every(3).Error(errTimeout)
+//	inj.On().Probability(0.1).Error(errUnavailable)
+//	inj.On("Put").Latency(50 * time.Millisecond)
+//
+//	m := newMockStore(withInjector(inj))
+//
+// Rules apply to the methods they are declared on, or to all methods if none is given. Errors are only
+// injected in methods whose last result is an error. Probabilities are drawn from a source seeded with
+// the seed given to New, so that test runs are reproducible.
+package fault
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Injector decides which faults to inject in each call. It is safe for concurrent use.
+type Injector struct {
+	mu    sync.Mutex
+	rng   *rand.Rand
+	rules []*Rule
+}
+
+// New returns an injector without rules, whose probabilities are drawn from a source seeded with seed.
+func New(seed uint64) *Injector {
+	return &Injector{rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// On adds a rule that applies to the given methods, or to all methods if none is given.
+// The rule triggers on every call until it is restricted with Every or Probability.
+func (i *Injector) On(methods ...string) *Rule {
+	r := &Rule{inj: i, methods: methods, every: 1, probability: 1}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = append(i.rules, r)
+	return r
+}
+
+// Inject applies the rules to a call to method. It waits for the injected latency, panics if a panic
+// is injected, and returns the injected error if canFail is true, i.e. if the method returns an error.
+// It does nothing on a nil injector.
+func (i *Injector) Inject(method string, canFail bool) error {
+	return i.InjectContext(context.Background(), method, canFail)
+}
+
+// InjectContext is like Inject, for methods that take a context. The injected latency is cut short when
+// ctx is done, in which case the context's error is returned if canFail is true.
+func (i *Injector) InjectContext(ctx context.Context, method string, canFail bool) error {
+	if i == nil {
+		return nil
+	}
+	f := i.faults(method, canFail)
+
+	if f.latency > 0 {
+		t := time.NewTimer(f.latency)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			if canFail {
+				return ctx.Err()
+			}
+		}
+	}
+	if f.panics {
+		panic(f.panicValue)
+	}
+	if canFail {
+		return f.err
+	}
+	return nil
+}
+
+// faults collects the faults of the rules that trigger on a call
+type faults struct {
+	latency    time.Duration
+	err        error
+	panics     bool
+	panicValue any
+}
+
+func (i *Injector) faults(method string, canFail bool) faults {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var f faults
+	for _, r := range i.rules {
+		if !r.triggers(method, canFail) {
+			continue
+		}
+		f.latency += r.latency
+		if f.err == nil {
+			f.err = r.err
+		}
+		if r.panics && !f.panics {
+			f.panics, f.panicValue = true, r.panicValue
+		}
+	}
+	return f
+}
+
+// Rule describes when and which faults are injected. The faults of all the rules that trigger
+// on a call are combined: latencies add up, and the first error and panic are used.
+type Rule struct {
+	inj         *Injector
+	methods     []string
+	every       int
+	probability float64
+	calls       int
+
+	latency    time.Duration
+	err        error
+	panics     bool
+	panicValue any
+}
+
+// Every restricts the rule to every nth call of the methods it applies to.
+func (r *Rule) Every(n int) *Rule {
+	r.inj.mu.Lock()
+	defer r.inj.mu.Unlock()
+	r.every = max(n, 1)
+	return r
+}
+
+// Probability restricts the rule to calls drawn with probability p, between 0 and 1.
+func (r *Rule) Probability(p float64) *Rule {
+	r.inj.mu.Lock()
+	defer r.inj.mu.Unlock()
+	r.probability = p
+	return r
+}
+
+// Latency delays the calls by d.
+func (r *Rule) Latency(d time.Duration) *Rule {
+	r.inj.mu.Lock()
+	defer r.inj.mu.Unlock()
+	r.latency = d
+	return r
+}
+
+// Error makes the calls return err, for methods whose last result is an error.
+func (r *Rule) Error(err error) *Rule {
+	r.inj.mu.Lock()
+	defer r.inj.mu.Unlock()
+	r.err = err
+	return r
+}
+
+// Panic makes the calls panic with v.
+func (r *Rule) Panic(v any) *Rule {
+	r.inj.mu.Lock()
+	defer r.inj.mu.Unlock()
+	r.panics, r.panicValue = true, v
+	return r
+}
+
+// Reports whether the rule triggers on a call to method. Must be called with the injector locked.
+func (r *Rule) triggers(method string, canFail bool) bool {
+	if len(r.methods) > 0 && !slices.Contains(r.methods, method) {
+		return false
+	}
+	// rules that only inject errors don't count the calls of methods that can't fail
+	if r.err != nil && r.latency == 0 && !r.panics && !canFail {
+		return false
+	}
+	r.calls++
+	if r.calls%r.every != 0 {
+		return false
+	}
+	return r.probability >= 1 || r.inj.rng.Float64() < r.probability
+}
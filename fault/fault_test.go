@@ -0,0 +1,110 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errInjected = errors.New("injected")
+
+func TestInjector(t *testing.T) {
+	t.Run("nil injector", func(t *testing.T) {
+		var inj *Injector
+		assert.Nil(t, inj.Inject("Get", true))
+	})
+
+	t.Run("every call", func(t *testing.T) {
+		inj := New(1)
+		inj.On("Get").Error(errInjected)
+
+		assert.ErrorIs(t, inj.Inject("Get", true), errInjected)
+		assert.ErrorIs(t, inj.Inject("Get", true), errInjected)
+		assert.Nil(t, inj.Inject("Put", true))
+	})
+
+	t.Run("every nth call", func(t *testing.T) {
+		inj := New(1)
+		inj.On("Get").Every(3).Error(errInjected)
+
+		var errs []error
+		for range 6 {
+			errs = append(errs, inj.Inject("Get", true))
+		}
+		assert.Equal(t, []error{nil, nil, errInjected, nil, nil, errInjected}, errs)
+	})
+
+	t.Run("all methods", func(t *testing.T) {
+		inj := New(1)
+		inj.On().Error(errInjected)
+
+		assert.ErrorIs(t, inj.Inject("Get", true), errInjected)
+		assert.ErrorIs(t, inj.Inject("Put", true), errInjected)
+		assert.Nil(t, inj.Inject("Len", false))
+	})
+
+	t.Run("methods that can't fail aren't counted", func(t *testing.T) {
+		inj := New(1)
+		inj.On().Every(2).Error(errInjected)
+
+		assert.Nil(t, inj.Inject("Get", true))
+		assert.Nil(t, inj.Inject("Len", false))
+		assert.ErrorIs(t, inj.Inject("Get", true), errInjected)
+	})
+
+	t.Run("probability", func(t *testing.T) {
+		run := func() (n int) {
+			inj := New(42)
+			inj.On("Get").Probability(0.5).Error(errInjected)
+			for range 1000 {
+				if inj.Inject("Get", true) != nil {
+					n++
+				}
+			}
+			return n
+		}
+		n := run()
+		assert.InDelta(t, 500, n, 100)
+		assert.Equal(t, n, run(), "the same seed injects the same errors")
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		inj := New(1)
+		inj.On("Get").Latency(20 * time.Millisecond)
+
+		start := time.Now()
+		assert.Nil(t, inj.Inject("Get", true))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("latency with done context", func(t *testing.T) {
+		inj := New(1)
+		inj.On("Get").Latency(time.Hour)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, inj.InjectContext(ctx, "Get", true), context.Canceled)
+		assert.Nil(t, inj.InjectContext(ctx, "Get", false))
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		inj := New(1)
+		inj.On("Len").Every(2).Panic("boom")
+
+		assert.NotPanics(t, func() { inj.Inject("Len", false) })
+		assert.PanicsWithValue(t, "boom", func() { inj.Inject("Len", false) })
+	})
+
+	t.Run("combined rules", func(t *testing.T) {
+		errOther := errors.New("other")
+		inj := New(1)
+		inj.On("Get").Error(errInjected)
+		inj.On().Error(errOther)
+
+		assert.ErrorIs(t, inj.Inject("Get", true), errInjected)
+		assert.ErrorIs(t, inj.Inject("Put", true), errOther)
+	})
+}
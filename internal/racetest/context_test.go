@@ -0,0 +1,17 @@
+package racetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextAware(t *testing.T) {
+	m := newMockStore()
+	assert.Nil(t, m.Sync(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, m.Sync(ctx), context.Canceled)
+}
@@ -0,0 +1,19 @@
+package racetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultError(t *testing.T) {
+	m := &mockStructStore{}
+
+	_, err := m.Get("k")
+	assert.EqualError(t, err, "mockStructStore.Get: not implemented")
+	assert.EqualError(t, m.Put("k", "v"), "mockStructStore.Put: not implemented")
+	assert.Equal(t, 0, m.Len())
+
+	m.PutFunc = func(string, string) error { return nil }
+	assert.Nil(t, m.Put("k", "v"))
+}
@@ -0,0 +1,65 @@
+package racetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memStore is a working in-memory Store for the mocks to delegate to
+type memStore struct {
+	mu sync.Mutex
+	m  map[string]string
+}
+
+func (s *memStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func (s *memStore) Put(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = value
+	return nil
+}
+
+func (s *memStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.m)
+}
+
+func (s *memStore) Sync(context.Context) error {
+	return nil
+}
+
+func TestDelegate(t *testing.T) {
+	t.Run("spy", func(t *testing.T) {
+		m := newMockStore(withDelegate(&memStore{m: map[string]string{}}))
+
+		assert.Nil(t, m.Put("k", "v"))
+		v, err := m.Get("k")
+		assert.Nil(t, err)
+		assert.Equal(t, "v", v)
+		assert.Equal(t, 1, m.Len())
+
+		assert.Equal(t, []mockStorePutCall{{Key: "k", Value: "v"}}, m.PutCalls())
+		assert.Equal(t, []mockStoreGetCall{{Key: "k"}}, m.GetCalls())
+	})
+
+	t.Run("partial override", func(t *testing.T) {
+		m := newMockStore(withDelegate(&memStore{m: map[string]string{}}), withReturnLen(42))
+
+		assert.Nil(t, m.Put("k", "v"))
+		assert.Equal(t, 42, m.Len())
+	})
+}
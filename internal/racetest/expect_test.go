@@ -0,0 +1,82 @@
+package racetest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/vibridi/gomock/v3/expect"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpectations(t *testing.T) {
+	t.Run("matched calls", func(t *testing.T) {
+		errNotFound := errors.New("not found")
+		m := newMockExpectStore(t, withReturnExpectStoreLen(7))
+		m.ExpectGet(expect.Eq("id")).Return("v", nil).Times(2)
+		m.ExpectGet(expect.Any()).Return("", errNotFound)
+		m.ExpectPut("id", expect.Func("non empty", func(s string) bool { return s != "" })).AnyTimes()
+
+		for range 2 {
+			v, err := m.Get("id")
+			assert.Equal(t, "v", v)
+			assert.Nil(t, err)
+		}
+		_, err := m.Get("id")
+		assert.ErrorIs(t, err, errNotFound)
+		assert.Nil(t, m.Put("id", "v"))
+		// methods without expectations use the options
+		assert.Equal(t, 7, m.Len())
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		m := newMockExpectStore(t)
+		m.ExpectPut(expect.Any(), expect.Any()).Times(goroutines * iterations)
+
+		var wg sync.WaitGroup
+		for range goroutines {
+			wg.Go(func() {
+				for range iterations {
+					assert.Nil(t, m.Put("k", "v"))
+				}
+			})
+		}
+		wg.Wait()
+	})
+
+	t.Run("missing calls", func(t *testing.T) {
+		ft := &failures{TB: t}
+		m := newMockExpectStore(ft)
+		m.ExpectGet("id")
+		ft.cleanup()
+		assert.Equal(t, []string{`missing call to Get("id"): called 0 of 1 times`}, ft.errors)
+	})
+}
+
+// failures records the failures of a test instead of reporting them
+type failures struct {
+	testing.TB
+	mu       sync.Mutex
+	errors   []string
+	cleanups []func()
+}
+
+func (f *failures) Helper() {}
+
+func (f *failures) Errorf(format string, args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *failures) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *failures) cleanup() {
+	for _, fn := range f.cleanups {
+		fn()
+	}
+}
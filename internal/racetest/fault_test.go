@@ -0,0 +1,62 @@
+package racetest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vibridi/gomock/v3/fault"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+func TestFaults(t *testing.T) {
+	t.Run("retry", func(t *testing.T) {
+		inj := fault.New(1)
+		inj.On("Get").Every(2).Error(errUnavailable)
+		m := newMockStore(withReturnGet("v", nil), withInjector(inj))
+
+		get := func() (v string, err error) {
+			for range 2 {
+				if v, err = m.Get("k"); err == nil {
+					return v, nil
+				}
+			}
+			return "", err
+		}
+		for range 4 {
+			v, err := get()
+			assert.Nil(t, err)
+			assert.Equal(t, "v", v)
+		}
+		assert.Len(t, m.GetCalls(), 7)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		inj := fault.New(1)
+		inj.On().Every(4).Error(errUnavailable)
+		m := newMockStore(withInjector(inj))
+
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			failed int
+		)
+		for range goroutines {
+			wg.Go(func() {
+				for range iterations {
+					if m.Put("k", "v") != nil {
+						mu.Lock()
+						failed++
+						mu.Unlock()
+					}
+					m.Len()
+				}
+			})
+		}
+		wg.Wait()
+		assert.Equal(t, goroutines*iterations/4, failed)
+	})
+}
@@ -0,0 +1,66 @@
+package racetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGates(t *testing.T) {
+	t.Run("release", func(t *testing.T) {
+		m := newMockStore(withReturnLen(3))
+		g := m.GateLen()
+
+		results := make(chan int, goroutines)
+		for range goroutines {
+			go func() {
+				results <- m.Len()
+			}()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := m.WaitLen(ctx, goroutines)
+		require.Nil(t, err)
+		assert.Empty(t, results)
+
+		g.Release()
+		for range goroutines {
+			assert.Equal(t, 3, <-results)
+		}
+		// a released gate lets the next calls through
+		assert.Equal(t, 3, m.Len())
+	})
+
+	t.Run("release with values", func(t *testing.T) {
+		errUnavailable := errors.New("unavailable")
+		m := newMockStore()
+		g := m.GateGet()
+
+		type result struct {
+			v   string
+			err error
+		}
+		done := make(chan result)
+		go func() {
+			v, err := m.Get("k")
+			done <- result{v, err}
+		}()
+		g.ReleaseWith("", errUnavailable)
+		g.Release()
+		assert.Equal(t, result{"", errUnavailable}, <-done)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		m := newMockStore()
+		m.GateSync()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := m.Sync(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,606 @@
+package racetest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/vibridi/gomock/v3/expect"
+)
+
+// generated by gomock: do not edit below this line
+
+//gomock:args -f racetest.go -d --local --delegate --canned-returns --record --expect --sequence --verify-overrides --name ExpectStore
+
+type mockExpectStore struct {
+	mu        sync.RWMutex
+	options   mockExpectStoreOptions
+	expect    *expect.Controller
+	countGet  int
+	countPut  int
+	countLen  int
+	countSync int
+
+	notify     chan struct{}
+	callsGet   []mockExpectStoreGetCall
+	calledGet  []chan mockExpectStoreGetCall
+	callsPut   []mockExpectStorePutCall
+	calledPut  []chan mockExpectStorePutCall
+	callsLen   []mockExpectStoreLenCall
+	calledLen  []chan mockExpectStoreLenCall
+	callsSync  []mockExpectStoreSyncCall
+	calledSync []chan mockExpectStoreSyncCall
+}
+
+type mockExpectStoreOptions struct {
+	funcGet  func(key string) (string, error)
+	funcPut  func(key string, value string) error
+	funcLen  func() int
+	funcSync func(ctx context.Context) error
+
+	sequence     *expect.Sequence
+	overrideGet  bool
+	overridePut  bool
+	overrideLen  bool
+	overrideSync bool
+}
+
+var defaultMockExpectStoreOptions = mockExpectStoreOptions{
+	funcGet: func(key string) (string, error) {
+		return "", nil
+	},
+	funcPut: func(key string, value string) error {
+		return nil
+	},
+	funcLen: func() int {
+		return 0
+	},
+	funcSync: func(ctx context.Context) error {
+		return nil
+	},
+}
+
+type mockExpectStoreOption func(*mockExpectStoreOptions)
+
+func withFuncExpectStoreGet(f func(key string) (string, error)) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcGet = f
+		o.overrideGet = true
+	}
+}
+
+func withFuncExpectStorePut(f func(key string, value string) error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcPut = f
+		o.overridePut = true
+	}
+}
+
+func withFuncExpectStoreLen(f func() int) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcLen = f
+		o.overrideLen = true
+	}
+}
+
+func withFuncExpectStoreSync(f func(ctx context.Context) error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcSync = f
+		o.overrideSync = true
+	}
+}
+
+func withDelegateExpectStore(impl Store) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcGet = impl.Get
+		o.overrideGet = false
+		o.funcPut = impl.Put
+		o.overridePut = false
+		o.funcLen = impl.Len
+		o.overrideLen = false
+		o.funcSync = impl.Sync
+		o.overrideSync = false
+	}
+}
+
+func withRecorderExpectStore(seq *expect.Sequence) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.sequence = seq
+	}
+}
+
+func withReturnExpectStoreGet(r0 string, r1 error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcGet = func(string) (string, error) {
+			return r0, r1
+		}
+		o.overrideGet = true
+	}
+}
+
+func withErrorExpectStoreGet(err error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcGet = func(string) (string, error) {
+			return "", err
+		}
+		o.overrideGet = true
+	}
+}
+
+func withReturnExpectStorePut(r0 error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcPut = func(string, string) error {
+			return r0
+		}
+		o.overridePut = true
+	}
+}
+
+func withErrorExpectStorePut(err error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcPut = func(string, string) error {
+			return err
+		}
+		o.overridePut = true
+	}
+}
+
+func withReturnExpectStoreLen(r0 int) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcLen = func() int {
+			return r0
+		}
+		o.overrideLen = true
+	}
+}
+
+func withReturnExpectStoreSync(r0 error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcSync = func(context.Context) error {
+			return r0
+		}
+		o.overrideSync = true
+	}
+}
+
+func withErrorExpectStoreSync(err error) mockExpectStoreOption {
+	return func(o *mockExpectStoreOptions) {
+		o.funcSync = func(context.Context) error {
+			return err
+		}
+		o.overrideSync = true
+	}
+}
+
+type mockExpectStoreGetResult struct {
+	R0 string
+	R1 error
+}
+
+type mockExpectStorePutResult struct {
+	R0 error
+}
+
+type mockExpectStoreLenResult struct {
+	R0 int
+}
+
+type mockExpectStoreSyncResult struct {
+	R0 error
+}
+
+func (m *mockExpectStore) Get(key string) (string, error) {
+	m.mu.Lock()
+	m.recordGet(mockExpectStoreGetCall{key})
+	m.countGet++
+	f := m.options.funcGet
+	m.options.sequence.Add("ExpectStore.Get", key)
+	m.mu.Unlock()
+	if c := m.expect.Call("Get", key); c != nil {
+		r, _ := c.Result().(mockExpectStoreGetResult)
+		return r.R0, r.R1
+	}
+	return f(key)
+}
+
+func (m *mockExpectStore) Put(key string, value string) error {
+	m.mu.Lock()
+	m.recordPut(mockExpectStorePutCall{key, value})
+	m.countPut++
+	f := m.options.funcPut
+	m.options.sequence.Add("ExpectStore.Put", key, value)
+	m.mu.Unlock()
+	if c := m.expect.Call("Put", key, value); c != nil {
+		r, _ := c.Result().(mockExpectStorePutResult)
+		return r.R0
+	}
+	return f(key, value)
+}
+
+func (m *mockExpectStore) Len() int {
+	m.mu.Lock()
+	m.recordLen(mockExpectStoreLenCall{})
+	m.countLen++
+	f := m.options.funcLen
+	m.options.sequence.Add("ExpectStore.Len")
+	m.mu.Unlock()
+	if c := m.expect.Call("Len"); c != nil {
+		r, _ := c.Result().(mockExpectStoreLenResult)
+		return r.R0
+	}
+	return f()
+}
+
+func (m *mockExpectStore) Sync(ctx context.Context) error {
+	m.mu.Lock()
+	m.recordSync(mockExpectStoreSyncCall{ctx})
+	m.countSync++
+	f := m.options.funcSync
+	m.options.sequence.Add("ExpectStore.Sync", ctx)
+	m.mu.Unlock()
+	if c := m.expect.Call("Sync", ctx); c != nil {
+		r, _ := c.Result().(mockExpectStoreSyncResult)
+		return r.R0
+	}
+	return f(ctx)
+}
+
+type mockExpectStoreGetCall struct {
+	Key string
+}
+
+func (m *mockExpectStore) GetCalls() []mockExpectStoreGetCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsGet)
+}
+
+func (m *mockExpectStore) GetCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsGet)
+}
+
+func (m *mockExpectStore) WaitGet(ctx context.Context, n int) ([]mockExpectStoreGetCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsGet)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockExpectStore.Get: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockExpectStore) GetCalled() <-chan mockExpectStoreGetCall {
+	ch := make(chan mockExpectStoreGetCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledGet = append(m.calledGet, ch)
+	return ch
+}
+
+func (m *mockExpectStore) recordGet(call mockExpectStoreGetCall) {
+	m.callsGet = append(m.callsGet, call)
+	for _, ch := range m.calledGet {
+		ch <- call
+	}
+	m.calledGet = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockExpectStorePutCall struct {
+	Key   string
+	Value string
+}
+
+func (m *mockExpectStore) PutCalls() []mockExpectStorePutCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsPut)
+}
+
+func (m *mockExpectStore) PutCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsPut)
+}
+
+func (m *mockExpectStore) WaitPut(ctx context.Context, n int) ([]mockExpectStorePutCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsPut)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockExpectStore.Put: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockExpectStore) PutCalled() <-chan mockExpectStorePutCall {
+	ch := make(chan mockExpectStorePutCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledPut = append(m.calledPut, ch)
+	return ch
+}
+
+func (m *mockExpectStore) recordPut(call mockExpectStorePutCall) {
+	m.callsPut = append(m.callsPut, call)
+	for _, ch := range m.calledPut {
+		ch <- call
+	}
+	m.calledPut = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockExpectStoreLenCall struct{}
+
+func (m *mockExpectStore) LenCalls() []mockExpectStoreLenCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsLen)
+}
+
+func (m *mockExpectStore) LenCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsLen)
+}
+
+func (m *mockExpectStore) WaitLen(ctx context.Context, n int) ([]mockExpectStoreLenCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsLen)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockExpectStore.Len: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockExpectStore) LenCalled() <-chan mockExpectStoreLenCall {
+	ch := make(chan mockExpectStoreLenCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledLen = append(m.calledLen, ch)
+	return ch
+}
+
+func (m *mockExpectStore) recordLen(call mockExpectStoreLenCall) {
+	m.callsLen = append(m.callsLen, call)
+	for _, ch := range m.calledLen {
+		ch <- call
+	}
+	m.calledLen = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockExpectStoreSyncCall struct {
+	Ctx context.Context
+}
+
+func (m *mockExpectStore) SyncCalls() []mockExpectStoreSyncCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsSync)
+}
+
+func (m *mockExpectStore) SyncCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsSync)
+}
+
+func (m *mockExpectStore) WaitSync(ctx context.Context, n int) ([]mockExpectStoreSyncCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsSync)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockExpectStore.Sync: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockExpectStore) SyncCalled() <-chan mockExpectStoreSyncCall {
+	ch := make(chan mockExpectStoreSyncCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledSync = append(m.calledSync, ch)
+	return ch
+}
+
+func (m *mockExpectStore) recordSync(call mockExpectStoreSyncCall) {
+	m.callsSync = append(m.callsSync, call)
+	for _, ch := range m.calledSync {
+		ch <- call
+	}
+	m.calledSync = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+func (m *mockExpectStore) AssertOverridesCalled(t testing.TB) bool {
+	t.Helper()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ok := true
+	if m.options.overrideGet && m.countGet == 0 {
+		t.Errorf("mockExpectStore.Get is overridden but was never called")
+		ok = false
+	}
+	if m.options.overridePut && m.countPut == 0 {
+		t.Errorf("mockExpectStore.Put is overridden but was never called")
+		ok = false
+	}
+	if m.options.overrideLen && m.countLen == 0 {
+		t.Errorf("mockExpectStore.Len is overridden but was never called")
+		ok = false
+	}
+	if m.options.overrideSync && m.countSync == 0 {
+		t.Errorf("mockExpectStore.Sync is overridden but was never called")
+		ok = false
+	}
+	return ok
+}
+
+type mockExpectStoreGetExpectation struct {
+	call *expect.Call
+}
+
+func (m *mockExpectStore) ExpectGet(key any) *mockExpectStoreGetExpectation {
+	return &mockExpectStoreGetExpectation{m.expect.Expect("Get", key)}
+}
+
+func (e *mockExpectStoreGetExpectation) Return(r0 string, r1 error) *mockExpectStoreGetExpectation {
+	e.call.SetResult(mockExpectStoreGetResult{r0, r1})
+	return e
+}
+
+func (e *mockExpectStoreGetExpectation) Times(n int) *mockExpectStoreGetExpectation {
+	e.call.Times(n)
+	return e
+}
+
+func (e *mockExpectStoreGetExpectation) AnyTimes() *mockExpectStoreGetExpectation {
+	e.call.AnyTimes()
+	return e
+}
+
+type mockExpectStorePutExpectation struct {
+	call *expect.Call
+}
+
+func (m *mockExpectStore) ExpectPut(key any, value any) *mockExpectStorePutExpectation {
+	return &mockExpectStorePutExpectation{m.expect.Expect("Put", key, value)}
+}
+
+func (e *mockExpectStorePutExpectation) Return(r0 error) *mockExpectStorePutExpectation {
+	e.call.SetResult(mockExpectStorePutResult{r0})
+	return e
+}
+
+func (e *mockExpectStorePutExpectation) Times(n int) *mockExpectStorePutExpectation {
+	e.call.Times(n)
+	return e
+}
+
+func (e *mockExpectStorePutExpectation) AnyTimes() *mockExpectStorePutExpectation {
+	e.call.AnyTimes()
+	return e
+}
+
+type mockExpectStoreLenExpectation struct {
+	call *expect.Call
+}
+
+func (m *mockExpectStore) ExpectLen() *mockExpectStoreLenExpectation {
+	return &mockExpectStoreLenExpectation{m.expect.Expect("Len")}
+}
+
+func (e *mockExpectStoreLenExpectation) Return(r0 int) *mockExpectStoreLenExpectation {
+	e.call.SetResult(mockExpectStoreLenResult{r0})
+	return e
+}
+
+func (e *mockExpectStoreLenExpectation) Times(n int) *mockExpectStoreLenExpectation {
+	e.call.Times(n)
+	return e
+}
+
+func (e *mockExpectStoreLenExpectation) AnyTimes() *mockExpectStoreLenExpectation {
+	e.call.AnyTimes()
+	return e
+}
+
+type mockExpectStoreSyncExpectation struct {
+	call *expect.Call
+}
+
+func (m *mockExpectStore) ExpectSync(ctx any) *mockExpectStoreSyncExpectation {
+	return &mockExpectStoreSyncExpectation{m.expect.Expect("Sync", ctx)}
+}
+
+func (e *mockExpectStoreSyncExpectation) Return(r0 error) *mockExpectStoreSyncExpectation {
+	e.call.SetResult(mockExpectStoreSyncResult{r0})
+	return e
+}
+
+func (e *mockExpectStoreSyncExpectation) Times(n int) *mockExpectStoreSyncExpectation {
+	e.call.Times(n)
+	return e
+}
+
+func (e *mockExpectStoreSyncExpectation) AnyTimes() *mockExpectStoreSyncExpectation {
+	e.call.AnyTimes()
+	return e
+}
+
+func newMockExpectStore(t testing.TB, opt ...mockExpectStoreOption) *mockExpectStore {
+	opts := defaultMockExpectStoreOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	m := &mockExpectStore{
+		options: opts,
+		expect:  expect.NewController(t),
+	}
+	t.Cleanup(func() {
+		m.AssertOverridesCalled(t)
+	})
+	return m
+}
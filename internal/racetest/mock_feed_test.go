@@ -0,0 +1,88 @@
+package racetest
+
+import (
+	"context"
+	"iter"
+)
+
+// generated by gomock: do not edit below this line
+
+//gomock:args -f racetest.go -i Feed -d --local --safe-defaults
+
+type mockFeed struct {
+	options mockFeedOptions
+}
+
+type mockFeedOptions struct {
+	funcSubscribe func(topic string) <-chan string
+	funcAll       func() iter.Seq[string]
+	funcPairs     func() iter.Seq2[int, string]
+	funcWatch     func(ctx context.Context) (func() error, error)
+}
+
+var defaultMockFeedOptions = mockFeedOptions{
+	funcSubscribe: func(topic string) <-chan string {
+		return func() <-chan string { c := make(chan string); close(c); return c }()
+	},
+	funcAll: func() iter.Seq[string] {
+		return func(yield func(string) bool) {}
+	},
+	funcPairs: func() iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {}
+	},
+	funcWatch: func(ctx context.Context) (func() error, error) {
+		return func() error { return nil }, nil
+	},
+}
+
+type mockFeedOption func(*mockFeedOptions)
+
+func withFuncFeedSubscribe(f func(topic string) <-chan string) mockFeedOption {
+	return func(o *mockFeedOptions) {
+		o.funcSubscribe = f
+	}
+}
+
+func withFuncFeedAll(f func() iter.Seq[string]) mockFeedOption {
+	return func(o *mockFeedOptions) {
+		o.funcAll = f
+	}
+}
+
+func withFuncFeedPairs(f func() iter.Seq2[int, string]) mockFeedOption {
+	return func(o *mockFeedOptions) {
+		o.funcPairs = f
+	}
+}
+
+func withFuncFeedWatch(f func(ctx context.Context) (func() error, error)) mockFeedOption {
+	return func(o *mockFeedOptions) {
+		o.funcWatch = f
+	}
+}
+
+func (m *mockFeed) Subscribe(topic string) <-chan string {
+	return m.options.funcSubscribe(topic)
+}
+
+func (m *mockFeed) All() iter.Seq[string] {
+	return m.options.funcAll()
+}
+
+func (m *mockFeed) Pairs() iter.Seq2[int, string] {
+	return m.options.funcPairs()
+}
+
+func (m *mockFeed) Watch(ctx context.Context) (func() error, error) {
+	return m.options.funcWatch(ctx)
+}
+
+func newMockFeed(opt ...mockFeedOption) Feed {
+	opts := defaultMockFeedOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	return &mockFeed{
+		options: opts,
+	}
+}
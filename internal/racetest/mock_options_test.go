@@ -0,0 +1,789 @@
+package racetest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vibridi/gomock/v3/expect"
+	"github.com/vibridi/gomock/v3/fault"
+)
+
+// generated by gomock: do not edit below this line
+
+//gomock:args -f racetest.go --local --delegate --canned-returns --sequenced-returns --record --sequence --gates --context-aware --faults --trace --reset
+
+type mockStore struct {
+	mu       sync.RWMutex
+	options  mockStoreOptions
+	gateGet  *mockStoreGetGate
+	gatePut  *mockStorePutGate
+	gateLen  *mockStoreLenGate
+	gateSync *mockStoreSyncGate
+
+	notify     chan struct{}
+	callsGet   []mockStoreGetCall
+	calledGet  []chan mockStoreGetCall
+	callsPut   []mockStorePutCall
+	calledPut  []chan mockStorePutCall
+	callsLen   []mockStoreLenCall
+	calledLen  []chan mockStoreLenCall
+	callsSync  []mockStoreSyncCall
+	calledSync []chan mockStoreSyncCall
+}
+
+type mockStoreOptions struct {
+	funcGet  func(key string) (string, error)
+	funcPut  func(key string, value string) error
+	funcLen  func() int
+	funcSync func(ctx context.Context) error
+
+	sequence *expect.Sequence
+	injector *fault.Injector
+	trace    func(method, args, results string, d time.Duration)
+}
+
+var defaultMockStoreOptions = mockStoreOptions{
+	funcGet: func(key string) (string, error) {
+		return "", nil
+	},
+	funcPut: func(key string, value string) error {
+		return nil
+	},
+	funcLen: func() int {
+		return 0
+	},
+	funcSync: func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return nil
+	},
+}
+
+type mockStoreOption func(*mockStoreOptions)
+
+func withFuncGet(f func(key string) (string, error)) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcGet = f
+	}
+}
+
+func withFuncPut(f func(key string, value string) error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcPut = f
+	}
+}
+
+func withFuncLen(f func() int) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcLen = f
+	}
+}
+
+func withFuncSync(f func(ctx context.Context) error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcSync = f
+	}
+}
+
+func withDelegate(impl Store) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcGet = impl.Get
+		o.funcPut = impl.Put
+		o.funcLen = impl.Len
+		o.funcSync = impl.Sync
+	}
+}
+
+func withRecorder(seq *expect.Sequence) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.sequence = seq
+	}
+}
+
+func withTrace(t testing.TB) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.trace = func(method, args, results string, d time.Duration) {
+			t.Logf("mockStore.%s(%s) -> (%s) in %s", method, args, results, d)
+		}
+	}
+}
+
+func withTraceLogger(l *slog.Logger) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.trace = func(method, args, results string, d time.Duration) {
+			l.Info("mockStore."+method, "args", args, "results", results, "duration", d)
+		}
+	}
+}
+
+func withInjector(inj *fault.Injector) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.injector = inj
+	}
+}
+
+func withReturnGet(r0 string, r1 error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcGet = func(string) (string, error) {
+			return r0, r1
+		}
+	}
+}
+
+func withErrorGet(err error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcGet = func(string) (string, error) {
+			return "", err
+		}
+	}
+}
+
+func withReturnPut(r0 error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcPut = func(string, string) error {
+			return r0
+		}
+	}
+}
+
+func withErrorPut(err error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcPut = func(string, string) error {
+			return err
+		}
+	}
+}
+
+func withReturnLen(r0 int) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcLen = func() int {
+			return r0
+		}
+	}
+}
+
+func withReturnSync(r0 error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcSync = func(context.Context) error {
+			return r0
+		}
+	}
+}
+
+func withErrorSync(err error) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		o.funcSync = func(context.Context) error {
+			return err
+		}
+	}
+}
+
+// mockStoreExhausted selects what a method returns once its sequence of return values is exhausted
+type mockStoreExhausted int
+
+const (
+	mockStoreRepeatLast mockStoreExhausted = iota // return the last values again
+	mockStoreReturnZero                           // return zero values
+	mockStoreFail                                 // panic
+)
+
+type mockStoreGetResult struct {
+	R0 string
+	R1 error
+}
+
+func withReturnsGet(results []mockStoreGetResult, exhausted mockStoreExhausted) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		var (
+			mu sync.Mutex
+			n  int
+		)
+		o.funcGet = func(string) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			var r mockStoreGetResult
+			switch {
+			case n < len(results):
+				r = results[n]
+				n++
+			case exhausted == mockStoreFail:
+				panic("mockStore.Get: no more return values")
+			case exhausted == mockStoreRepeatLast && len(results) > 0:
+				r = results[len(results)-1]
+			}
+			return r.R0, r.R1
+		}
+	}
+}
+
+type mockStorePutResult struct {
+	R0 error
+}
+
+func withReturnsPut(results []mockStorePutResult, exhausted mockStoreExhausted) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		var (
+			mu sync.Mutex
+			n  int
+		)
+		o.funcPut = func(string, string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			var r mockStorePutResult
+			switch {
+			case n < len(results):
+				r = results[n]
+				n++
+			case exhausted == mockStoreFail:
+				panic("mockStore.Put: no more return values")
+			case exhausted == mockStoreRepeatLast && len(results) > 0:
+				r = results[len(results)-1]
+			}
+			return r.R0
+		}
+	}
+}
+
+type mockStoreLenResult struct {
+	R0 int
+}
+
+func withReturnsLen(results []mockStoreLenResult, exhausted mockStoreExhausted) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		var (
+			mu sync.Mutex
+			n  int
+		)
+		o.funcLen = func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			var r mockStoreLenResult
+			switch {
+			case n < len(results):
+				r = results[n]
+				n++
+			case exhausted == mockStoreFail:
+				panic("mockStore.Len: no more return values")
+			case exhausted == mockStoreRepeatLast && len(results) > 0:
+				r = results[len(results)-1]
+			}
+			return r.R0
+		}
+	}
+}
+
+type mockStoreSyncResult struct {
+	R0 error
+}
+
+func withReturnsSync(results []mockStoreSyncResult, exhausted mockStoreExhausted) mockStoreOption {
+	return func(o *mockStoreOptions) {
+		var (
+			mu sync.Mutex
+			n  int
+		)
+		o.funcSync = func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			var r mockStoreSyncResult
+			switch {
+			case n < len(results):
+				r = results[n]
+				n++
+			case exhausted == mockStoreFail:
+				panic("mockStore.Sync: no more return values")
+			case exhausted == mockStoreRepeatLast && len(results) > 0:
+				r = results[len(results)-1]
+			}
+			return r.R0
+		}
+	}
+}
+
+func (m *mockStore) Get(key string) (r0 string, r1 error) {
+	m.mu.Lock()
+	m.recordGet(mockStoreGetCall{key})
+	f := m.options.funcGet
+	g := m.gateGet
+	inj := m.options.injector
+	tr := m.options.trace
+	m.options.sequence.Add("Store.Get", key)
+	m.mu.Unlock()
+	if tr != nil {
+		defer func(start time.Time) {
+			tr("Get", fmt.Sprintf("%v", key), fmt.Sprintf("%v, %v", r0, r1), time.Since(start))
+		}(time.Now())
+	}
+	if g != nil {
+		<-g.released
+		if r := g.result; r != nil {
+			return r.R0, r.R1
+		}
+	}
+	if err := inj.Inject("Get", true); err != nil {
+		return "", err
+	}
+	return f(key)
+}
+
+func (m *mockStore) Put(key string, value string) (r0 error) {
+	m.mu.Lock()
+	m.recordPut(mockStorePutCall{key, value})
+	f := m.options.funcPut
+	g := m.gatePut
+	inj := m.options.injector
+	tr := m.options.trace
+	m.options.sequence.Add("Store.Put", key, value)
+	m.mu.Unlock()
+	if tr != nil {
+		defer func(start time.Time) {
+			tr("Put", fmt.Sprintf("%v, %v", key, value), fmt.Sprintf("%v", r0), time.Since(start))
+		}(time.Now())
+	}
+	if g != nil {
+		<-g.released
+		if r := g.result; r != nil {
+			return r.R0
+		}
+	}
+	if err := inj.Inject("Put", true); err != nil {
+		return err
+	}
+	return f(key, value)
+}
+
+func (m *mockStore) Len() (r0 int) {
+	m.mu.Lock()
+	m.recordLen(mockStoreLenCall{})
+	f := m.options.funcLen
+	g := m.gateLen
+	inj := m.options.injector
+	tr := m.options.trace
+	m.options.sequence.Add("Store.Len")
+	m.mu.Unlock()
+	if tr != nil {
+		defer func(start time.Time) {
+			tr("Len", "", fmt.Sprintf("%v", r0), time.Since(start))
+		}(time.Now())
+	}
+	if g != nil {
+		<-g.released
+		if r := g.result; r != nil {
+			return r.R0
+		}
+	}
+	inj.Inject("Len", false)
+	return f()
+}
+
+func (m *mockStore) Sync(ctx context.Context) (r0 error) {
+	m.mu.Lock()
+	m.recordSync(mockStoreSyncCall{ctx})
+	f := m.options.funcSync
+	g := m.gateSync
+	inj := m.options.injector
+	tr := m.options.trace
+	m.options.sequence.Add("Store.Sync", ctx)
+	m.mu.Unlock()
+	if tr != nil {
+		defer func(start time.Time) {
+			tr("Sync", fmt.Sprintf("%v", ctx), fmt.Sprintf("%v", r0), time.Since(start))
+		}(time.Now())
+	}
+	if g != nil {
+		select {
+		case <-g.released:
+		case <-ctx.Done():
+			err := ctx.Err()
+			return err
+		}
+		if r := g.result; r != nil {
+			return r.R0
+		}
+	}
+	if err := inj.InjectContext(ctx, "Sync", true); err != nil {
+		return err
+	}
+	return f(ctx)
+}
+
+type mockStoreGetCall struct {
+	Key string
+}
+
+func (m *mockStore) GetCalls() []mockStoreGetCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsGet)
+}
+
+func (m *mockStore) GetCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsGet)
+}
+
+func (m *mockStore) WaitGet(ctx context.Context, n int) ([]mockStoreGetCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsGet)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStore.Get: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStore) GetCalled() <-chan mockStoreGetCall {
+	ch := make(chan mockStoreGetCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledGet = append(m.calledGet, ch)
+	return ch
+}
+
+func (m *mockStore) recordGet(call mockStoreGetCall) {
+	m.callsGet = append(m.callsGet, call)
+	for _, ch := range m.calledGet {
+		ch <- call
+	}
+	m.calledGet = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockStorePutCall struct {
+	Key   string
+	Value string
+}
+
+func (m *mockStore) PutCalls() []mockStorePutCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsPut)
+}
+
+func (m *mockStore) PutCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsPut)
+}
+
+func (m *mockStore) WaitPut(ctx context.Context, n int) ([]mockStorePutCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsPut)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStore.Put: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStore) PutCalled() <-chan mockStorePutCall {
+	ch := make(chan mockStorePutCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledPut = append(m.calledPut, ch)
+	return ch
+}
+
+func (m *mockStore) recordPut(call mockStorePutCall) {
+	m.callsPut = append(m.callsPut, call)
+	for _, ch := range m.calledPut {
+		ch <- call
+	}
+	m.calledPut = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockStoreLenCall struct{}
+
+func (m *mockStore) LenCalls() []mockStoreLenCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsLen)
+}
+
+func (m *mockStore) LenCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsLen)
+}
+
+func (m *mockStore) WaitLen(ctx context.Context, n int) ([]mockStoreLenCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsLen)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStore.Len: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStore) LenCalled() <-chan mockStoreLenCall {
+	ch := make(chan mockStoreLenCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledLen = append(m.calledLen, ch)
+	return ch
+}
+
+func (m *mockStore) recordLen(call mockStoreLenCall) {
+	m.callsLen = append(m.callsLen, call)
+	for _, ch := range m.calledLen {
+		ch <- call
+	}
+	m.calledLen = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockStoreSyncCall struct {
+	Ctx context.Context
+}
+
+func (m *mockStore) SyncCalls() []mockStoreSyncCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsSync)
+}
+
+func (m *mockStore) SyncCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsSync)
+}
+
+func (m *mockStore) WaitSync(ctx context.Context, n int) ([]mockStoreSyncCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsSync)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStore.Sync: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStore) SyncCalled() <-chan mockStoreSyncCall {
+	ch := make(chan mockStoreSyncCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledSync = append(m.calledSync, ch)
+	return ch
+}
+
+func (m *mockStore) recordSync(call mockStoreSyncCall) {
+	m.callsSync = append(m.callsSync, call)
+	for _, ch := range m.calledSync {
+		ch <- call
+	}
+	m.calledSync = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockStoreGetGate struct {
+	released chan struct{}
+	once     sync.Once
+	result   *mockStoreGetResult
+}
+
+func (m *mockStore) GateGet() *mockStoreGetGate {
+	g := &mockStoreGetGate{released: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gateGet = g
+	return g
+}
+
+func (g *mockStoreGetGate) Release() {
+	g.once.Do(func() {
+		close(g.released)
+	})
+}
+
+func (g *mockStoreGetGate) ReleaseWith(r0 string, r1 error) {
+	g.once.Do(func() {
+		g.result = &mockStoreGetResult{r0, r1}
+		close(g.released)
+	})
+}
+
+type mockStorePutGate struct {
+	released chan struct{}
+	once     sync.Once
+	result   *mockStorePutResult
+}
+
+func (m *mockStore) GatePut() *mockStorePutGate {
+	g := &mockStorePutGate{released: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gatePut = g
+	return g
+}
+
+func (g *mockStorePutGate) Release() {
+	g.once.Do(func() {
+		close(g.released)
+	})
+}
+
+func (g *mockStorePutGate) ReleaseWith(r0 error) {
+	g.once.Do(func() {
+		g.result = &mockStorePutResult{r0}
+		close(g.released)
+	})
+}
+
+type mockStoreLenGate struct {
+	released chan struct{}
+	once     sync.Once
+	result   *mockStoreLenResult
+}
+
+func (m *mockStore) GateLen() *mockStoreLenGate {
+	g := &mockStoreLenGate{released: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gateLen = g
+	return g
+}
+
+func (g *mockStoreLenGate) Release() {
+	g.once.Do(func() {
+		close(g.released)
+	})
+}
+
+func (g *mockStoreLenGate) ReleaseWith(r0 int) {
+	g.once.Do(func() {
+		g.result = &mockStoreLenResult{r0}
+		close(g.released)
+	})
+}
+
+type mockStoreSyncGate struct {
+	released chan struct{}
+	once     sync.Once
+	result   *mockStoreSyncResult
+}
+
+func (m *mockStore) GateSync() *mockStoreSyncGate {
+	g := &mockStoreSyncGate{released: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gateSync = g
+	return g
+}
+
+func (g *mockStoreSyncGate) Release() {
+	g.once.Do(func() {
+		close(g.released)
+	})
+}
+
+func (g *mockStoreSyncGate) ReleaseWith(r0 error) {
+	g.once.Do(func() {
+		g.result = &mockStoreSyncResult{r0}
+		close(g.released)
+	})
+}
+
+func (m *mockStore) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	opts := defaultMockStoreOptions
+	opts.sequence = m.options.sequence
+	opts.injector = m.options.injector
+	opts.trace = m.options.trace
+	m.options = opts
+	m.callsGet = nil
+	m.callsPut = nil
+	m.callsLen = nil
+	m.callsSync = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+	m.gateGet = nil
+	m.gatePut = nil
+	m.gateLen = nil
+	m.gateSync = nil
+}
+
+func (m *mockStore) Apply(opt ...mockStoreOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, o := range opt {
+		o(&m.options)
+	}
+}
+
+func newMockStore(opt ...mockStoreOption) *mockStore {
+	opts := defaultMockStoreOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	return &mockStore{
+		options: opts,
+	}
+}
@@ -0,0 +1,298 @@
+package racetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// generated by gomock: do not edit below this line
+
+//gomock:args -f racetest.go --local --struct --record --name StructStore --default-error not-implemented
+
+type mockStructStore struct {
+	GetFunc  func(key string) (string, error)
+	PutFunc  func(key string, value string) error
+	LenFunc  func() int
+	SyncFunc func(ctx context.Context) error
+
+	mu         sync.RWMutex
+	notify     chan struct{}
+	callsGet   []mockStructStoreGetCall
+	calledGet  []chan mockStructStoreGetCall
+	callsPut   []mockStructStorePutCall
+	calledPut  []chan mockStructStorePutCall
+	callsLen   []mockStructStoreLenCall
+	calledLen  []chan mockStructStoreLenCall
+	callsSync  []mockStructStoreSyncCall
+	calledSync []chan mockStructStoreSyncCall
+}
+
+func (m *mockStructStore) Get(key string) (string, error) {
+	m.mu.Lock()
+	m.recordGet(mockStructStoreGetCall{key})
+	m.mu.Unlock()
+	if m.GetFunc != nil {
+		return m.GetFunc(key)
+	}
+	return "", errors.New("mockStructStore.Get: not implemented")
+}
+
+func (m *mockStructStore) Put(key string, value string) error {
+	m.mu.Lock()
+	m.recordPut(mockStructStorePutCall{key, value})
+	m.mu.Unlock()
+	if m.PutFunc != nil {
+		return m.PutFunc(key, value)
+	}
+	return errors.New("mockStructStore.Put: not implemented")
+}
+
+func (m *mockStructStore) Len() int {
+	m.mu.Lock()
+	m.recordLen(mockStructStoreLenCall{})
+	m.mu.Unlock()
+	if m.LenFunc != nil {
+		return m.LenFunc()
+	}
+	return 0
+}
+
+func (m *mockStructStore) Sync(ctx context.Context) error {
+	m.mu.Lock()
+	m.recordSync(mockStructStoreSyncCall{ctx})
+	m.mu.Unlock()
+	if m.SyncFunc != nil {
+		return m.SyncFunc(ctx)
+	}
+	return errors.New("mockStructStore.Sync: not implemented")
+}
+
+type mockStructStoreGetCall struct {
+	Key string
+}
+
+func (m *mockStructStore) GetCalls() []mockStructStoreGetCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsGet)
+}
+
+func (m *mockStructStore) GetCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsGet)
+}
+
+func (m *mockStructStore) WaitGet(ctx context.Context, n int) ([]mockStructStoreGetCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsGet)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStructStore.Get: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStructStore) GetCalled() <-chan mockStructStoreGetCall {
+	ch := make(chan mockStructStoreGetCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledGet = append(m.calledGet, ch)
+	return ch
+}
+
+func (m *mockStructStore) recordGet(call mockStructStoreGetCall) {
+	m.callsGet = append(m.callsGet, call)
+	for _, ch := range m.calledGet {
+		ch <- call
+	}
+	m.calledGet = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockStructStorePutCall struct {
+	Key   string
+	Value string
+}
+
+func (m *mockStructStore) PutCalls() []mockStructStorePutCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsPut)
+}
+
+func (m *mockStructStore) PutCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsPut)
+}
+
+func (m *mockStructStore) WaitPut(ctx context.Context, n int) ([]mockStructStorePutCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsPut)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStructStore.Put: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStructStore) PutCalled() <-chan mockStructStorePutCall {
+	ch := make(chan mockStructStorePutCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledPut = append(m.calledPut, ch)
+	return ch
+}
+
+func (m *mockStructStore) recordPut(call mockStructStorePutCall) {
+	m.callsPut = append(m.callsPut, call)
+	for _, ch := range m.calledPut {
+		ch <- call
+	}
+	m.calledPut = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockStructStoreLenCall struct{}
+
+func (m *mockStructStore) LenCalls() []mockStructStoreLenCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsLen)
+}
+
+func (m *mockStructStore) LenCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsLen)
+}
+
+func (m *mockStructStore) WaitLen(ctx context.Context, n int) ([]mockStructStoreLenCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsLen)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStructStore.Len: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStructStore) LenCalled() <-chan mockStructStoreLenCall {
+	ch := make(chan mockStructStoreLenCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledLen = append(m.calledLen, ch)
+	return ch
+}
+
+func (m *mockStructStore) recordLen(call mockStructStoreLenCall) {
+	m.callsLen = append(m.callsLen, call)
+	for _, ch := range m.calledLen {
+		ch <- call
+	}
+	m.calledLen = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockStructStoreSyncCall struct {
+	Ctx context.Context
+}
+
+func (m *mockStructStore) SyncCalls() []mockStructStoreSyncCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsSync)
+}
+
+func (m *mockStructStore) SyncCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsSync)
+}
+
+func (m *mockStructStore) WaitSync(ctx context.Context, n int) ([]mockStructStoreSyncCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsSync)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockStructStore.Sync: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockStructStore) SyncCalled() <-chan mockStructStoreSyncCall {
+	ch := make(chan mockStructStoreSyncCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledSync = append(m.calledSync, ch)
+	return ch
+}
+
+func (m *mockStructStore) recordSync(call mockStructStoreSyncCall) {
+	m.callsSync = append(m.callsSync, call)
+	for _, ch := range m.calledSync {
+		ch <- call
+	}
+	m.calledSync = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
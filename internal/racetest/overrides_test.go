@@ -0,0 +1,32 @@
+package racetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertOverridesCalled(t *testing.T) {
+	t.Run("called", func(t *testing.T) {
+		ft := &failures{TB: t}
+		m := newMockExpectStore(ft, withReturnExpectStoreGet("v", nil), withDelegateExpectStore(&memStore{}))
+		m.Len()
+		assert.True(t, m.AssertOverridesCalled(ft))
+		assert.Empty(t, ft.errors)
+	})
+
+	t.Run("never called", func(t *testing.T) {
+		ft := &failures{TB: t}
+		m := newMockExpectStore(ft, withReturnExpectStoreGet("v", nil), withErrorExpectStorePut(errUnavailable))
+		assert.ErrorIs(t, m.Put("k", "v"), errUnavailable)
+		assert.False(t, m.AssertOverridesCalled(ft))
+		assert.Equal(t, []string{"mockExpectStore.Get is overridden but was never called"}, ft.errors)
+	})
+
+	t.Run("when the test ends", func(t *testing.T) {
+		ft := &failures{TB: t}
+		newMockExpectStore(ft, withFuncExpectStoreLen(func() int { return 1 }))
+		ft.cleanup()
+		assert.Equal(t, []string{"mockExpectStore.Len is overridden but was never called"}, ft.errors)
+	})
+}
@@ -0,0 +1,74 @@
+package racetest
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The mocks in this package are generated by gomock and kept up to date by TestCheck in the main package.
+// Run with -race to verify that they are safe for concurrent use.
+
+const (
+	goroutines = 16
+	iterations = 100
+)
+
+func TestOptionsStyle(t *testing.T) {
+	t.Run("concurrent calls", func(t *testing.T) {
+		m := newMockStore(
+			withFuncGet(func(key string) (string, error) {
+				return "v" + key, nil
+			}),
+		)
+
+		var wg sync.WaitGroup
+		for g := range goroutines {
+			wg.Go(func() {
+				for i := range iterations {
+					key := strconv.Itoa(g*iterations + i)
+					v, err := m.Get(key)
+					assert.Nil(t, err)
+					assert.Equal(t, "v"+key, v)
+					assert.Nil(t, m.Put(key, v))
+					_ = m.Len()
+					_ = m.GetCalls()
+					_ = m.PutCallCount()
+				}
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, goroutines*iterations, m.GetCallCount())
+		assert.Equal(t, goroutines*iterations, m.PutCallCount())
+		assert.Equal(t, goroutines*iterations, m.LenCallCount())
+	})
+}
+
+func TestStructStyle(t *testing.T) {
+	m := &mockStructStore{
+		PutFunc: func(key, value string) error {
+			return nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Go(func() {
+			for i := range iterations {
+				key := strconv.Itoa(g*iterations + i)
+				assert.Nil(t, m.Put(key, key))
+				_, _ = m.Get(key)
+				_ = m.PutCalls()
+				_ = m.GetCallCount()
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, goroutines*iterations, m.PutCallCount())
+	assert.Equal(t, goroutines*iterations, m.GetCallCount())
+	assert.Zero(t, m.LenCallCount())
+}
@@ -0,0 +1,22 @@
+// Package racetest declares interfaces whose generated mocks are exercised
+// by the tests in this package, including under the race detector.
+package racetest
+
+import (
+	"context"
+	"iter"
+)
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+	Len() int
+	Sync(ctx context.Context) error
+}
+
+type Feed interface {
+	Subscribe(topic string) <-chan string
+	All() iter.Seq[string]
+	Pairs() iter.Seq2[int, string]
+	Watch(ctx context.Context) (stop func() error, err error)
+}
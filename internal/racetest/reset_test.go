@@ -0,0 +1,79 @@
+package racetest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vibridi/gomock/v3/fault"
+)
+
+func TestReset(t *testing.T) {
+	t.Run("table", func(t *testing.T) {
+		m := newMockStore()
+		for _, c := range []struct {
+			opts []mockStoreOption
+			want string
+		}{
+			{nil, ""},
+			{[]mockStoreOption{withReturnGet("v1", nil)}, "v1"},
+			{[]mockStoreOption{withReturnGet("v2", nil)}, "v2"},
+			{nil, ""},
+		} {
+			m.Reset()
+			m.Apply(c.opts...)
+
+			v, err := m.Get("k")
+			assert.Nil(t, err)
+			assert.Equal(t, c.want, v)
+			assert.Len(t, m.GetCalls(), 1)
+		}
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		m := newMockStore(withReturnLen(1))
+		m.Apply(withReturnGet("v", nil))
+
+		v, _ := m.Get("k")
+		assert.Equal(t, "v", v)
+		assert.Equal(t, 1, m.Len())
+	})
+
+	t.Run("clears gates", func(t *testing.T) {
+		m := newMockStore()
+		m.GateLen()
+		m.Reset()
+		assert.Equal(t, 0, m.Len())
+	})
+
+	t.Run("keeps the injector", func(t *testing.T) {
+		inj := fault.New(1)
+		inj.On("Get").Error(errUnavailable)
+		m := newMockStore(withInjector(inj))
+		m.Reset()
+
+		_, err := m.Get("k")
+		assert.ErrorIs(t, err, errUnavailable)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		m := newMockStore()
+
+		var wg sync.WaitGroup
+		for i := range goroutines {
+			wg.Go(func() {
+				for range iterations {
+					if i%2 == 0 {
+						m.Apply(withReturnLen(i))
+						m.Reset()
+					} else {
+						m.Len()
+						m.Put("k", "v")
+					}
+				}
+			})
+		}
+		wg.Wait()
+	})
+}
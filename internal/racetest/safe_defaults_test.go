@@ -0,0 +1,32 @@
+package racetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeDefaults(t *testing.T) {
+	m := newMockFeed()
+
+	t.Run("closed channel", func(t *testing.T) {
+		_, ok := <-m.Subscribe("topic")
+		assert.False(t, ok)
+	})
+
+	t.Run("empty iterators", func(t *testing.T) {
+		for range m.All() {
+			t.Fatal("unexpected value")
+		}
+		for range m.Pairs() {
+			t.Fatal("unexpected pair")
+		}
+	})
+
+	t.Run("no-op function", func(t *testing.T) {
+		stop, err := m.Watch(context.Background())
+		assert.Nil(t, err)
+		assert.Nil(t, stop())
+	})
+}
@@ -0,0 +1,33 @@
+package racetest
+
+import (
+	"testing"
+
+	"github.com/vibridi/gomock/v3/expect"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallOrder(t *testing.T) {
+	seq := expect.NewSequence()
+	cache := newMockStore(withRecorder(seq))
+	db := newMockExpectStore(t, withRecorderExpectStore(seq))
+	db.ExpectGet("id").Return("v", nil)
+
+	if _, err := cache.Get("id"); err == nil {
+		v, _ := db.Get("id")
+		_ = cache.Put("id", v)
+	}
+
+	seq.AssertOrder(t, "Store.Get", "ExpectStore.Get", "Store.Put")
+	seq.AssertPartialOrder(t, "Store.Get", "Store.Put")
+
+	ft := &failures{TB: t}
+	assert.False(t, seq.AssertPartialOrder(ft, "Store.Put", "ExpectStore.Get"))
+	assert.Equal(t, []string{"calls don't match the expected partial order, missing ExpectStore.Get\n" +
+		"expected: Store.Put, ExpectStore.Get\n" +
+		"actual:\n" +
+		"\t1. Store.Get(\"id\")\n" +
+		"\t2. ExpectStore.Get(\"id\")\n" +
+		"\t3. Store.Put(\"id\", \"v\")"}, ft.errors)
+}
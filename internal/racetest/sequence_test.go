@@ -0,0 +1,86 @@
+package racetest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequencedReturns(t *testing.T) {
+	errNotFound := errors.New("not found")
+	results := []mockStoreGetResult{{"a", nil}, {"b", nil}, {"", errNotFound}}
+
+	t.Run("repeat last", func(t *testing.T) {
+		m := newMockStore(withReturnsGet(results, mockStoreRepeatLast))
+		for _, want := range []string{"a", "b", "", ""} {
+			v, _ := m.Get("k")
+			assert.Equal(t, want, v)
+		}
+		_, err := m.Get("k")
+		assert.ErrorIs(t, err, errNotFound)
+	})
+
+	t.Run("return zero", func(t *testing.T) {
+		m := newMockStore(withReturnsGet(results[:1], mockStoreReturnZero))
+		v, err := m.Get("k")
+		assert.Equal(t, "a", v)
+		assert.Nil(t, err)
+		v, err = m.Get("k")
+		assert.Equal(t, "", v)
+		assert.Nil(t, err)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		m := newMockStore(withReturnsLen([]mockStoreLenResult{{1}}, mockStoreFail))
+		assert.Equal(t, 1, m.Len())
+		assert.PanicsWithValue(t, "mockStore.Len: no more return values", func() { m.Len() })
+	})
+
+	t.Run("consumed once across goroutines", func(t *testing.T) {
+		seq := make([]mockStoreLenResult, goroutines*iterations)
+		for i := range seq {
+			seq[i] = mockStoreLenResult{i}
+		}
+		m := newMockStore(withReturnsLen(seq, mockStoreFail))
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			seen = make(map[int]bool)
+		)
+		for range goroutines {
+			wg.Go(func() {
+				for range iterations {
+					n := m.Len()
+					mu.Lock()
+					seen[n] = true
+					mu.Unlock()
+				}
+			})
+		}
+		wg.Wait()
+		assert.Len(t, seen, len(seq))
+	})
+}
+
+func TestCannedReturns(t *testing.T) {
+	errNotFound := errors.New("not found")
+
+	m := newMockStore(
+		withReturnGet("a", nil),
+		withErrorPut(errNotFound),
+		withReturnLen(3),
+	)
+	v, err := m.Get("k")
+	assert.Equal(t, "a", v)
+	assert.Nil(t, err)
+	assert.ErrorIs(t, m.Put("k", "v"), errNotFound)
+	assert.Equal(t, 3, m.Len())
+
+	m = newMockStore(withErrorGet(errNotFound))
+	v, err = m.Get("k")
+	assert.Equal(t, "", v)
+	assert.ErrorIs(t, err, errNotFound)
+}
@@ -0,0 +1,74 @@
+package racetest
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace(t *testing.T) {
+	t.Run("logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey || a.Key == "duration" {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		m := newMockStore(withReturnGet("v", nil), withTraceLogger(l))
+		m.Get("k")
+		m.Put("k", "v")
+		m.Len()
+
+		assert.Equal(t, []string{
+			`level=INFO msg=mockStore.Get args=k results="v, <nil>"`,
+			`level=INFO msg=mockStore.Put args="k, v" results=<nil>`,
+			`level=INFO msg=mockStore.Len args="" results=0`,
+		}, strings.Split(strings.TrimSpace(buf.String()), "\n"))
+	})
+
+	t.Run("test log", func(t *testing.T) {
+		ft := &logs{TB: t}
+		m := newMockStore(withErrorPut(errUnavailable), withTrace(ft))
+		m.Put("k", "v")
+
+		assert.Len(t, ft.logs, 1)
+		assert.Regexp(t, `^mockStore\.Put\(k, v\) -> \(unavailable\) in \S+$`, ft.logs[0])
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		ft := &logs{TB: t}
+		m := newMockStore(withTrace(ft))
+
+		var wg sync.WaitGroup
+		for range goroutines {
+			wg.Go(func() {
+				for range iterations {
+					m.Put("k", "v")
+				}
+			})
+		}
+		wg.Wait()
+		assert.Len(t, ft.logs, goroutines*iterations)
+	})
+}
+
+// logs records the logs of a test instead of printing them
+type logs struct {
+	testing.TB
+	mu   sync.Mutex
+	logs []string
+}
+
+func (l *logs) Logf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logs = append(l.logs, fmt.Sprintf(format, args...))
+}
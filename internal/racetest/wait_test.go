@@ -0,0 +1,54 @@
+package racetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWait(t *testing.T) {
+	t.Run("calls from goroutines", func(t *testing.T) {
+		m := newMockStore()
+		for g := range goroutines {
+			go func() {
+				_ = m.Put("k", string(rune('a'+g)))
+			}()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		calls, err := m.WaitPut(ctx, goroutines)
+		require.Nil(t, err)
+		assert.Len(t, calls, goroutines)
+	})
+
+	t.Run("context expires", func(t *testing.T) {
+		m := &mockStructStore{}
+		_, _ = m.Get("a")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		calls, err := m.WaitGet(ctx, 2)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.EqualError(t, err, "mockStructStore.Get: got 1 of 2 calls [{Key:a}]: context deadline exceeded")
+		assert.Equal(t, []mockStructStoreGetCall{{"a"}}, calls)
+	})
+
+	t.Run("called channel", func(t *testing.T) {
+		m := newMockStore()
+		called := m.GetCalled()
+		go func() {
+			_, _ = m.Get("a")
+		}()
+
+		select {
+		case c := <-called:
+			assert.Equal(t, "a", c.Key)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Get was not called")
+		}
+	})
+}
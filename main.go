@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +21,7 @@ import (
 func main() {
 	if err := run(os.Args); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		os.Exit(1)
 	}
 }
 
@@ -26,146 +30,564 @@ func run(args []string) error {
 
 	app.Name = "gomock"
 	app.Usage = "simple interface mocking tool"
-	app.UsageText = "gomock { help | [options] filename }"
+	app.UsageText = "gomock { help | generate [--config FILE] | regen [patterns] | check [patterns] | [options] filename | patterns }"
 	app.UseShortOptionHandling = true
 	app.Version = version.Version()
 
-	var (
-		sourceFile    string
-		destination   string
-		target        string
-		noQualify     bool
-		export        bool
-		unnamedsig    bool
-		structStyle   bool
-		mockName      string
-		underlying    cli.StringSlice
-		aliases       cli.StringSlice
-		disambiguate  bool
-		prefixPackage bool
-	)
+	o := &options{}
+	app.Flags = o.flags()
 
-	app.Flags = []cli.Flag{
-		&cli.StringFlag{
+	var configFile string
+
+	app.Commands = []*cli.Command{
+		{
+			Name:  "generate",
+			Usage: "Generate all mock targets listed in the project config file",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:        "config",
+					Aliases:     []string{"c"},
+					Usage:       "Read targets from `FILE` (default: gomock.yaml, gomock.yml or .gomock.json at the module root)",
+					Destination: &configFile,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return generateConfig(configFile)
+			},
+		},
+		{
+			Name:      "regen",
+			Usage:     "Regenerate every mock from the invocation recorded in its file",
+			ArgsUsage: "[patterns]",
+			Action: func(c *cli.Context) error {
+				return regen(c.Args().Slice())
+			},
+		},
+		{
+			Name:      "check",
+			Usage:     "Report mocks that differ from what their recorded invocation generates, without writing them",
+			ArgsUsage: "[patterns]",
+			Action: func(c *cli.Context) error {
+				return check(c.Args().Slice())
+			},
+		},
+	}
+
+	app.Action = func(c *cli.Context) error {
+		if len(o.sourceFiles) == 0 && isPattern(c.Args().Get(0)) {
+			return generatePackages(c.Args().Slice(), o)
+		}
+		if len(o.sourceFiles) == 0 {
+			o.sourceFiles = c.Args().Slice()
+		}
+		return generate(o)
+	}
+
+	return app.Run(args)
+}
+
+// options holds the flag values that control the generation of one mock.
+type options struct {
+	sourceFiles   fileList
+	destination   string
+	target        string
+	noQualify     bool
+	export        bool
+	unnamedsig    bool
+	structStyle   bool
+	mockName      string
+	underlying    cli.StringSlice
+	aliases       cli.StringSlice
+	disambiguate  bool
+	prefixPackage bool
+	dryRun        bool
+	diff          bool
+	all           bool
+	layout        string
+	methods       cli.StringSlice
+	excluded      cli.StringSlice
+	strict        bool
+	delegate      bool
+	canned        bool
+	sequenced     bool
+	record        bool
+	expect        bool
+	sequence      bool
+	gates         bool
+	contextAware  bool
+	faults        bool
+	verify        bool
+	trace         bool
+	reset         bool
+	safeDefaults  bool
+	defaultError  string
+
+	// set by the mocks layout, which writes mocks to another package
+	pkg          string // package clause of a new destination file
+	sourceImport string // import path of the source package
+}
+
+func (o *options) flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.GenericFlag{
 			Name:        "f",
-			Usage:       "Read input from `FILE`. Must be valid Go code",
-			Destination: &sourceFile,
+			Usage:       "Read input from `FILE`. Must be valid Go code. Repeat the option or use a glob pattern to parse several files together, or use '-' to read from stdin",
+			Destination: &o.sourceFiles,
 		},
 		&cli.StringFlag{
 			Name:        "o",
 			Usage:       "Write output to `FILE`",
 			Value:       "",
-			Destination: &destination,
+			Destination: &o.destination,
 		},
 		&cli.StringFlag{
 			Name:        "i",
 			Usage:       "Mock the interface named `IDENTIFIER`",
 			Value:       "",
-			Destination: &target,
+			Destination: &o.target,
 		},
 		&cli.BoolFlag{
 			Name:        "x",
 			Usage:       "Export 'with' and 'new' functions",
-			Destination: &export,
+			Destination: &o.export,
 		},
 		&cli.BoolFlag{
 			Name:        "u",
 			Usage:       "Output func signatures with unnamed parameters where possible",
-			Destination: &unnamedsig,
+			Destination: &o.unnamedsig,
 		},
 		&cli.BoolFlag{
 			Name:        "d",
 			Usage:       "Disambiguate 'withFunc' identifiers with service name, e.g. withFuncMyServiceGet()",
-			Destination: &disambiguate,
+			Destination: &o.disambiguate,
 		},
 		&cli.BoolFlag{
 			Name:        "p",
 			Usage:       "Merge the package name and the mock name in function identifiers, e.g. foo.Client gives NewMockFooClient",
-			Destination: &prefixPackage,
+			Destination: &o.prefixPackage,
 		},
 		&cli.BoolFlag{
 			Name:        "local",
 			Usage:       "Don't qualify types with the package name",
-			Destination: &noQualify,
+			Destination: &o.noQualify,
 		},
 		&cli.BoolFlag{
 			Name:        "struct",
 			Usage:       "Prints the output mock in struct style (default: options style)",
-			Destination: &structStyle,
+			Destination: &o.structStyle,
 		},
 		&cli.StringFlag{
 			Name:        "name",
 			Usage:       "Use `NAME` in output types instead of the name of the mocked interface",
 			Value:       "",
-			Destination: &mockName,
+			Destination: &o.mockName,
 		},
 		&cli.StringSliceFlag{
 			Name:        "utype",
 			Usage:       "Maps a type to its underlying type. `MAPPING` must in the format 'type=underlying'. If pkgs option is specified, the map key must be the aliased type.",
 			Value:       nil,
-			Destination: &underlying,
+			Destination: &o.underlying,
 		},
 		&cli.StringSliceFlag{
 			Name:        "pkgs",
 			Usage:       "Maps package names to custom import aliases. `MAPPING` must in the format 'package=alias'",
 			Value:       nil,
-			Destination: &aliases,
+			Destination: &o.aliases,
+		},
+		&cli.StringSliceFlag{
+			Name:        "methods",
+			Usage:       "Generate helpers only for the `METHODS` in the comma-separated list. The other methods panic when called",
+			Destination: &o.methods,
+		},
+		&cli.StringSliceFlag{
+			Name:        "exclude-methods",
+			Usage:       "Don't generate helpers for the `METHODS` in the comma-separated list. These methods panic when called",
+			Destination: &o.excluded,
+		},
+		&cli.BoolFlag{
+			Name:        "strict",
+			Usage:       "The mock constructor takes a testing.TB, and calls to methods without override fail the test",
+			Destination: &o.strict,
+		},
+		&cli.BoolFlag{
+			Name:        "delegate",
+			Usage:       "Generate a withDelegate(impl) option, which makes every method call through to a real implementation",
+			Destination: &o.delegate,
+		},
+		&cli.BoolFlag{
+			Name:        "canned-returns",
+			Usage:       "Generate withReturn and withError options, e.g. withReturnGet(v, err) and withErrorGet(err), which make every call return fixed values",
+			Destination: &o.canned,
+		},
+		&cli.BoolFlag{
+			Name:        "sequenced-returns",
+			Usage:       "Generate withReturns options, e.g. withReturnsGet(results, mockFooRepeatLast), which make successive calls return successive values",
+			Destination: &o.sequenced,
+		},
+		&cli.BoolFlag{
+			Name:        "record",
+			Usage:       "Record the calls to each method with their arguments, e.g. m.GetCalls() and m.GetCallCount(). The constructor returns the mock type",
+			Destination: &o.record,
+		},
+		&cli.BoolFlag{
+			Name:        "expect",
+			Usage:       "Generate an expectations API, e.g. m.ExpectGet(expect.Eq(\"id\")).Return(v, nil), verified when the test ends. The constructor takes a testing.TB and returns the mock type",
+			Destination: &o.expect,
+		},
+		&cli.BoolFlag{
+			Name:        "sequence",
+			Usage:       "Generate a withRecorder option that attaches the mock to an expect.Sequence, to verify the order of calls across mocks",
+			Destination: &o.sequence,
+		},
+		&cli.BoolFlag{
+			Name:        "gates",
+			Usage:       "Generate gates, e.g. m.GateGet(), that block the calls to a method until the test releases them",
+			Destination: &o.gates,
+		},
+		&cli.BoolFlag{
+			Name:        "context-aware",
+			Usage:       "Default implementations of methods with a context.Context parameter and an error result return ctx.Err() when the context is done",
+			Destination: &o.contextAware,
+		},
+		&cli.BoolFlag{
+			Name:        "faults",
+			Usage:       "Generate a withInjector option that attaches a fault.Injector to the mock, to inject latency, errors or panics in its calls",
+			Destination: &o.faults,
+		},
+		&cli.BoolFlag{
+			Name:        "verify-overrides",
+			Usage:       "Generate m.AssertOverridesCalled(t), which fails the test for each method overridden through an option but never called. The constructor returns the mock type, and runs the check when the test ends if it takes a testing.TB",
+			Destination: &o.verify,
+		},
+		&cli.BoolFlag{
+			Name:        "trace",
+			Usage:       "Generate withTrace(t) and withTraceLogger(l) options that log each call with its arguments, results and duration through testing.TB or a *slog.Logger",
+			Destination: &o.trace,
+		},
+		&cli.BoolFlag{
+			Name:        "reset",
+			Usage:       "Generate m.Reset(), which restores the default options and clears the recorded calls, and m.Apply(opts...), which applies options to an existing mock. The constructor returns the mock type",
+			Destination: &o.reset,
+		},
+		&cli.BoolFlag{
+			Name:        "safe-defaults",
+			Usage:       "Default implementations return closed channels, empty iter.Seq and iter.Seq2 iterators and no-op functions instead of nil",
+			Destination: &o.safeDefaults,
+		},
+		&cli.StringFlag{
+			Name:        "default-error",
+			Usage:       "Default implementations of methods whose last result is an error return `ERR`: either 'not-implemented', for an error naming the method, or a sentinel such as ErrNotImplemented, io.EOF or github.com/foo/errs.ErrNotImplemented",
+			Destination: &o.defaultError,
+		},
+		&cli.BoolFlag{
+			Name:        "dry-run",
+			Usage:       "Print the content that would be written to the output file, without modifying it",
+			Destination: &o.dryRun,
+		},
+		&cli.BoolFlag{
+			Name:        "diff",
+			Usage:       "Print a unified diff between the output file and the content that would be written to it, without modifying it",
+			Destination: &o.diff,
+		},
+		&cli.BoolFlag{
+			Name:        "all",
+			Usage:       "With package patterns, generate mocks for every exported interface, not only the annotated ones",
+			Destination: &o.all,
+		},
+		&cli.StringFlag{
+			Name:        "layout",
+			Usage:       "With --all, write each mock `LAYOUT`: 'beside' the source as mock_<iface>_test.go, or in a mirrored 'mocks' tree",
+			Value:       layoutBeside,
+			Destination: &o.layout,
 		},
 	}
+}
 
-	app.Action = func(c *cli.Context) error {
-		if mockName != "" && prefixPackage {
-			return fmt.Errorf("option conflict: specify only one of --name and -p")
+// Returns the command line arguments that reproduce the generation, excluding the destination.
+// The source files are made relative to dir, so that the arguments stay valid when the repository is moved.
+// Glob patterns are recorded as written, so that files added later are parsed on regeneration.
+func (o *options) args(dir string) ([]string, error) {
+	var args []string
+	for _, f := range o.sourceFiles {
+		src, err := filepath.Abs(f)
+		if err != nil {
+			return nil, err
 		}
-
-		if sourceFile == "" {
-			sourceFile = c.Args().Get(0)
+		if src, err = filepath.Rel(dir, src); err != nil {
+			return nil, err
 		}
-		_, _ = fmt.Fprintf(os.Stderr, "parsing %s\n", sourceFile)
-
-		if !strings.HasSuffix(sourceFile, ".go") {
-			return errors.New("source is not a Go file")
+		args = append(args, "-f", filepath.ToSlash(src))
+	}
+	if o.target != "" {
+		args = append(args, "-i", o.target)
+	}
+	flags := []struct {
+		name string
+		set  bool
+	}{
+		{"-x", o.export},
+		{"-u", o.unnamedsig},
+		{"-d", o.disambiguate},
+		{"-p", o.prefixPackage},
+		{"--local", o.noQualify},
+		{"--struct", o.structStyle},
+		{"--strict", o.strict},
+		{"--delegate", o.delegate},
+		{"--canned-returns", o.canned},
+		{"--sequenced-returns", o.sequenced},
+		{"--record", o.record},
+		{"--expect", o.expect},
+		{"--sequence", o.sequence},
+		{"--gates", o.gates},
+		{"--context-aware", o.contextAware},
+		{"--faults", o.faults},
+		{"--verify-overrides", o.verify},
+		{"--trace", o.trace},
+		{"--reset", o.reset},
+		{"--safe-defaults", o.safeDefaults},
+	}
+	for _, f := range flags {
+		if f.set {
+			args = append(args, f.name)
 		}
+	}
+	if o.mockName != "" {
+		args = append(args, "--name", o.mockName)
+	}
+	if o.defaultError != "" {
+		args = append(args, "--default-error", o.defaultError)
+	}
+	for _, u := range o.underlying.Value() {
+		args = append(args, "--utype", u)
+	}
+	for _, a := range o.aliases.Value() {
+		args = append(args, "--pkgs", a)
+	}
+	if m := o.methods.Value(); len(m) > 0 {
+		args = append(args, "--methods", strings.Join(m, ","))
+	}
+	if m := o.excluded.Value(); len(m) > 0 {
+		args = append(args, "--exclude-methods", strings.Join(m, ","))
+	}
+	return args, nil
+}
+
+// fileList is the value of a flag that can be repeated to list files. Unlike cli.StringSlice,
+// it doesn't split the values on commas, which are valid in file names.
+type fileList []string
 
-		f, err := filepath.Abs(sourceFile)
+func (l *fileList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func (l *fileList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+// Reports whether the source is read from stdin.
+func (o *options) stdin() bool {
+	return len(o.sourceFiles) == 1 && o.sourceFiles[0] == "-"
+}
+
+// Returns the source files, with glob patterns expanded. A pattern never matches the destination,
+// so that the mock isn't parsed when it is regenerated in the same directory as its sources.
+func (o *options) sources() ([]string, error) {
+	if o.stdin() {
+		return []string{"-"}, nil
+	}
+
+	var files []string
+	for _, f := range o.sourceFiles {
+		matches, err := filepath.Glob(f)
 		if err != nil {
-			return fmt.Errorf("failed to open file: %w", err)
+			return nil, fmt.Errorf("invalid source pattern: %w", err)
+		}
+		if len(matches) == 0 {
+			// not a pattern, or a pattern without matches: let the parser report the error
+			matches = []string{f}
 		}
+		for _, m := range matches {
+			if !strings.HasSuffix(m, ".go") {
+				return nil, errors.New("source is not a Go file")
+			}
+			if m != f && o.destination != "" && sameFile(m, o.destination) {
+				continue
+			}
+			files = append(files, m)
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.New("source is not a Go file")
+	}
+	return files, nil
+}
+
+// Reports whether the paths a and b refer to the same file.
+func sameFile(a, b string) bool {
+	a, errA := filepath.Abs(a)
+	b, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && a == b
+}
+
+// Parses command line arguments, as recorded by options.args, without running any action.
+func parseArgs(args []string) (*options, error) {
+	o := &options{}
+	app := &cli.App{
+		Name:                   "gomock",
+		Flags:                  o.flags(),
+		UseShortOptionHandling: true,
+		HideHelp:               true,
+		Action: func(c *cli.Context) error {
+			if len(o.sourceFiles) == 0 {
+				o.sourceFiles = c.Args().Slice()
+			}
+			return nil
+		},
+	}
+	if err := app.Run(append([]string{"gomock"}, args...)); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Generates the mock described by o and writes it to stdout or to the destination file.
+func generate(o *options) error {
+	if o.dryRun && o.diff {
+		return fmt.Errorf("option conflict: specify only one of --dry-run and --diff")
+	}
+	if (o.dryRun || o.diff) && o.destination == "" {
+		return fmt.Errorf("option conflict: --dry-run and --diff require -o")
+	}
+
+	out, err := mock(o)
+	if err != nil {
+		return err
+	}
+
+	if o.destination == "" {
+		fmt.Println(string(out))
+		return nil
+	}
 
-		md, err := parser.Parse(f, nil, target)
+	if o.dryRun || o.diff {
+		src, err := os.ReadFile(o.destination)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read destination file: %w", err)
+		}
+		b, err := writer.Content(o.destination, o.pkg, src, out)
+		if err != nil {
+			return fmt.Errorf("failed to write destination file: %w", err)
+		}
+		if o.dryRun {
+			fmt.Print(string(b))
+			return nil
+		}
+		d, err := diff(o.destination, src, b)
 		if err != nil {
 			return err
 		}
+		fmt.Print(d)
+		return nil
+	}
 
-		out, err := template.Exec(
-			md,
-			template.Opts{
-				Qualify:          !noQualify,
-				Export:           export,
-				UnnamedSignature: unnamedsig,
-				StructStyle:      structStyle,
-				Disambiguate:     disambiguate,
-				MockName:         mockName,
-				Underlying:       underlying.Value(),
-				ImportAliases:    aliases.Value(),
-				PrefixPackage:    prefixPackage,
-			},
-		)
+	if err := writer.File(o.destination, o.pkg, out); err != nil {
+		return fmt.Errorf("failed to write destination file: %w", err)
+	}
+
+	return nil
+}
+
+// Generates the mock described by o. If o has a destination, the output starts with
+// the recorded arguments.
+func mock(o *options) ([]byte, error) {
+	if o.mockName != "" && o.prefixPackage {
+		return nil, fmt.Errorf("option conflict: specify only one of --name and -p")
+	}
+
+	files, err := o.sources()
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "parsing %s\n", strings.Join(files, ", "))
+
+	var md *parser.MockData
+	switch {
+	case o.stdin():
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		md, err = parser.Parse("", src, o.target)
 		if err != nil {
-			return fmt.Errorf("failed to write output: %w", err)
+			return nil, err
 		}
 
-		if destination == "" {
-			fmt.Println(string(out))
-			return nil
+	case len(files) == 1:
+		f, err := filepath.Abs(files[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %w", err)
+		}
+		md, err = parser.Parse(f, nil, o.target)
+		if err != nil {
+			return nil, err
 		}
 
-		if err := writer.File(destination, "", out); err != nil {
-			return fmt.Errorf("failed to write destination file: %w", err)
+	default:
+		md, err = parser.ParseFiles(files, o.target)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		return nil
+	out, err := template.Exec(
+		md,
+		template.Opts{
+			Qualify:          !o.noQualify,
+			Export:           o.export,
+			UnnamedSignature: o.unnamedsig,
+			StructStyle:      o.structStyle,
+			Disambiguate:     o.disambiguate,
+			MockName:         o.mockName,
+			Underlying:       o.underlying.Value(),
+			ImportAliases:    o.aliases.Value(),
+			PrefixPackage:    o.prefixPackage,
+			Methods:          o.methods.Value(),
+			ExcludeMethods:   o.excluded.Value(),
+			Strict:           o.strict,
+			Delegate:         o.delegate,
+			CannedReturns:    o.canned,
+			SequencedReturns: o.sequenced,
+			Record:           o.record,
+			Expect:           o.expect,
+			Sequence:         o.sequence,
+			Gates:            o.gates,
+			ContextAware:     o.contextAware,
+			Faults:           o.faults,
+			VerifyOverrides:  o.verify,
+			Trace:            o.trace,
+			Reset:            o.reset,
+			SafeDefaults:     o.safeDefaults,
+			DefaultError:     o.defaultError,
+			SourceImport:     o.sourceImport,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write output: %w", err)
 	}
 
-	return app.Run(args)
+	// the arguments can't be recorded when the source is read from stdin
+	if o.destination == "" || o.stdin() {
+		return out, nil
+	}
+
+	dst, err := filepath.Abs(o.destination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write destination file: %w", err)
+	}
+	args, err := o.args(filepath.Dir(dst))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record arguments: %w", err)
+	}
+	return append([]byte(template.ArgsDirective+" "+joinArgs(args)+"\n\n"), bytes.TrimLeft(out, "\n")...), nil
 }
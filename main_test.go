@@ -3,10 +3,14 @@ package main
 import (
 	"io"
 	"os"
+	"os/exec"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/vibridi/gomock/v3/writer/template"
 )
 
 func TestRun(t *testing.T) {
@@ -69,10 +73,12 @@ func TestRun(t *testing.T) {
 	})
 
 	t.Run("write to file", func(t *testing.T) {
-		tmpdir := t.TempDir()
+		tmpdir := t.TempDir() + "/foo"
+		err := os.MkdirAll(tmpdir, 0755)
+		require.Nil(t, err)
 		tmpfile := tmpdir + "/foo.go"
 		outfile := tmpdir + "/out.go"
-		err := os.WriteFile(tmpfile, []byte("package foo\n\ntype Foo interface {\nDo() error\n}"), 0644)
+		err = os.WriteFile(tmpfile, []byte("package foo\n\ntype Foo interface {\nDo() error\n}"), 0644)
 		require.Nil(t, err)
 
 		err = run([]string{"gomock", "-f", tmpfile, "-o", outfile})
@@ -84,8 +90,319 @@ func TestRun(t *testing.T) {
 
 		out := readstr(f)
 		assert.Contains(t, out, "options mockFooOptions")
+		assert.Contains(t, out, "//gomock:args -f foo.go\n")
+	})
+
+	t.Run("read from stdin", func(t *testing.T) {
+		stdin := os.Stdin
+		r, w, err := os.Pipe()
+		require.Nil(t, err)
+		_, err = w.WriteString("package foo\n\ntype Foo interface {\nDo() error\n}")
+		require.Nil(t, err)
+		_ = w.Close()
+
+		os.Stdin = r
+		defer func() {
+			os.Stdin = stdin
+		}()
+
+		out := captureStdout(t, func() {
+			err := run([]string{"gomock", "-"})
+			require.Nil(t, err)
+		})
+		assert.Contains(t, out, "options mockFooOptions")
+	})
+
+	t.Run("multiple files", func(t *testing.T) {
+		tmpdir := t.TempDir() + "/foo"
+		err := os.MkdirAll(tmpdir, 0755)
+		require.Nil(t, err)
+		err = os.WriteFile(tmpdir+"/foo.go", []byte("package foo\n\ntype Foo interface {\nbar\nDo() error\n}"), 0644)
+		require.Nil(t, err)
+		err = os.WriteFile(tmpdir+"/bar.go", []byte("package foo\n\ntype bar interface {\nGet() string\n}"), 0644)
+		require.Nil(t, err)
+
+		out := captureStdout(t, func() {
+			err := run([]string{"gomock", "-f", tmpdir + "/foo.go", "-f", tmpdir + "/bar.go", "-i", "Foo"})
+			require.Nil(t, err)
+		})
+		assert.Contains(t, out, "func (m *mockFoo) Get() string {")
+
+		outfile := tmpdir + "/out.go"
+		err = run([]string{"gomock", "-f", tmpdir + "/*.go", "-i", "Foo", "-o", outfile})
+		require.Nil(t, err)
+		b, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "//gomock:args -f *.go -i Foo\n")
+		assert.Contains(t, string(b), "func (m *mockFoo) Get() string {")
+
+		// the pattern matches files added after the generation, but not the mock itself
+		err = os.WriteFile(tmpdir+"/foo.go", []byte("package foo\n\ntype Foo interface {\nbar\nbaz\nDo() error\n}"), 0644)
+		require.Nil(t, err)
+		err = os.WriteFile(tmpdir+"/baz.go", []byte("package foo\n\ntype baz interface {\nPut(v string)\n}"), 0644)
+		require.Nil(t, err)
+		err = run([]string{"gomock", "regen", outfile})
+		require.Nil(t, err)
+		b, err = os.ReadFile(outfile)
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "//gomock:args -f *.go -i Foo\n")
+		assert.Contains(t, string(b), "func (m *mockFoo) Put(v string) {")
+	})
+
+	t.Run("file name with comma", func(t *testing.T) {
+		tmpdir := t.TempDir()
+		tmpfile := tmpdir + "/foo,bar.go"
+		err := os.WriteFile(tmpfile, []byte("package foo\n\ntype Foo interface {\nDo() error\n}"), 0644)
+		require.Nil(t, err)
+
+		out := captureStdout(t, func() {
+			err := run([]string{"gomock", "-f", tmpfile})
+			require.Nil(t, err)
+		})
+		assert.Contains(t, out, "func (m *mockFoo) Do() error {")
+	})
+
+	t.Run("selected methods", func(t *testing.T) {
+		tmpdir := t.TempDir() + "/foo"
+		err := os.MkdirAll(tmpdir, 0755)
+		require.Nil(t, err)
+		tmpfile := tmpdir + "/foo.go"
+		outfile := tmpdir + "/out.go"
+		err = os.WriteFile(tmpfile, []byte("package foo\n\ntype Foo interface {\nGet() string\nPut(v string)\nDo() error\n}"), 0644)
+		require.Nil(t, err)
+
+		err = run([]string{"gomock", "-f", tmpfile, "-o", outfile, "--methods", "Get,Put", "--exclude-methods", "Put"})
+		require.Nil(t, err)
+		b, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "//gomock:args -f foo.go --methods Get,Put --exclude-methods Put\n")
+		assert.Contains(t, string(b), `panic("mockFoo.Do: not mocked")`)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		tmpdir := t.TempDir() + "/foo"
+		err := os.MkdirAll(tmpdir, 0755)
+		require.Nil(t, err)
+		tmpfile := tmpdir + "/foo.go"
+		outfile := tmpdir + "/out.go"
+		err = os.WriteFile(tmpfile, []byte("package foo\n\ntype Foo interface {\nGet() string\n}"), 0644)
+		require.Nil(t, err)
+
+		err = run([]string{"gomock", "-f", tmpfile, "-o", outfile, "--strict"})
+		require.Nil(t, err)
+		b, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "package foo\n\nimport (\n\t\"testing\"\n)\n\n"+template.Notice))
+		assert.Contains(t, string(b), "//gomock:args -f foo.go --strict\n")
+		assert.Contains(t, string(b), "func newMockFoo(t testing.TB, opt ...mockFooOption) foo.Foo {")
+	})
+
+	t.Run("expect", func(t *testing.T) {
+		tmpdir := t.TempDir() + "/foo"
+		err := os.MkdirAll(tmpdir, 0755)
+		require.Nil(t, err)
+		tmpfile := tmpdir + "/foo.go"
+		outfile := tmpdir + "/out.go"
+		err = os.WriteFile(tmpfile, []byte("package foo\n\ntype Foo interface {\nGet() string\n}"), 0644)
+		require.Nil(t, err)
+
+		err = run([]string{"gomock", "-f", tmpfile, "-o", outfile, "--expect"})
+		require.Nil(t, err)
+		b, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "package foo\n\nimport (\n\t\"testing\"\n\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\n"+template.Notice))
+		assert.Contains(t, string(b), "//gomock:args -f foo.go --expect\n")
+		assert.Contains(t, string(b), "func (m *mockFoo) ExpectGet() *mockFooGetExpectation {")
+	})
+
+	t.Run("dry run and diff conflict", func(t *testing.T) {
+		err := run([]string{"gomock", "-f", "foo.go", "-o", "out.go", "--dry-run", "--diff"})
+		assert.Equal(t, "option conflict: specify only one of --dry-run and --diff", err.Error())
+	})
+
+	t.Run("dry run without output file", func(t *testing.T) {
+		err := run([]string{"gomock", "-f", "foo.go", "--dry-run"})
+		assert.Equal(t, "option conflict: --dry-run and --diff require -o", err.Error())
 	})
 
+	t.Run("dry run and diff", func(t *testing.T) {
+		tmpdir := t.TempDir() + "/foo"
+		err := os.MkdirAll(tmpdir, 0755)
+		require.Nil(t, err)
+		tmpfile := tmpdir + "/foo.go"
+		outfile := tmpdir + "/out.go"
+		err = os.WriteFile(tmpfile, []byte("package foo\n\ntype Foo interface {\nDo() error\n}"), 0644)
+		require.Nil(t, err)
+		existing := "package foo\n\n" + template.Notice + "\n\nfunc handWritten() {}\n"
+		err = os.WriteFile(outfile, []byte(existing), 0644)
+		require.Nil(t, err)
+
+		out := captureStdout(t, func() {
+			err := run([]string{"gomock", "-f", tmpfile, "-o", outfile, "--dry-run"})
+			require.Nil(t, err)
+		})
+		assert.True(t, strings.HasPrefix(out, "package foo\n\n"+template.Notice+"\n\n//gomock:args -f foo.go\n"))
+		assert.Contains(t, out, "options mockFooOptions")
+
+		out = captureStdout(t, func() {
+			err := run([]string{"gomock", "-f", tmpfile, "-o", outfile, "--diff"})
+			require.Nil(t, err)
+		})
+		assert.Contains(t, out, "-func handWritten() {}")
+		assert.Contains(t, out, "+type mockFoo struct {")
+
+		b, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+		assert.Equal(t, existing, string(b))
+	})
+}
+
+func TestRegen(t *testing.T) {
+	tmpdir := t.TempDir()
+	err := os.MkdirAll(tmpdir+"/foo/mocks", 0755)
+	require.Nil(t, err)
+	srcfile := tmpdir + "/foo/foo.go"
+	outfile := tmpdir + "/foo/mocks/mocks_test.go"
+
+	err = os.WriteFile(srcfile, []byte("package foo\n\ntype Foo interface {\nDo() error\n}\ntype Bar interface {\nGet() string\n}"), 0644)
+	require.Nil(t, err)
+
+	err = run([]string{"gomock", "-f", srcfile, "-o", outfile, "-i", "Bar", "--struct", "-x", "--pkgs", "foo=foo2"})
+	require.Nil(t, err)
+
+	b, err := os.ReadFile(outfile)
+	require.Nil(t, err)
+	assert.Contains(t, string(b), "//gomock:args -f ../foo.go -i Bar -x --struct --pkgs foo=foo2\n")
+
+	err = os.WriteFile(srcfile, []byte("package foo\n\ntype Foo interface {\nDo() error\n}\ntype Bar interface {\nGet() string\nSet(v string)\n}"), 0644)
+	require.Nil(t, err)
+
+	t.Run("regenerate with recorded args", func(t *testing.T) {
+		err = run([]string{"gomock", "regen", tmpdir + "/..."})
+		require.Nil(t, err)
+
+		b, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+		out := string(b)
+		assert.Contains(t, out, "package mocks")
+		assert.Contains(t, out, "//gomock:args -f ../foo.go -i Bar -x --struct --pkgs foo=foo2\n")
+		assert.Contains(t, out, "type MockBar struct")
+		assert.Contains(t, out, "SetFunc func(v string)")
+	})
+
+	t.Run("skip files without recorded args", func(t *testing.T) {
+		files, err := goFiles([]string{tmpdir + "/..."})
+		require.Nil(t, err)
+		assert.Len(t, files, 2)
+
+		args, err := recordedArgs(srcfile)
+		require.Nil(t, err)
+		assert.Nil(t, args)
+	})
+
+	t.Run("non recursive pattern", func(t *testing.T) {
+		files, err := goFiles([]string{tmpdir + "/foo"})
+		require.Nil(t, err)
+		assert.Equal(t, []string{srcfile}, files)
+	})
+
+	t.Run("quoted args", func(t *testing.T) {
+		dir := t.TempDir() + "/foo"
+		err := os.MkdirAll(dir+"/my foo", 0755)
+		require.Nil(t, err)
+		err = os.WriteFile(dir+"/my foo/foo.go", []byte("package foo\n\ntype Foo interface {\nDo() error\n}"), 0644)
+		require.Nil(t, err)
+
+		err = run([]string{"gomock", "-f", dir + "/my foo/foo.go", "-o", dir + "/mock_test.go"})
+		require.Nil(t, err)
+
+		b, err := os.ReadFile(dir + "/mock_test.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), `//gomock:args -f "my foo/foo.go"`+"\n")
+
+		err = os.WriteFile(dir+"/my foo/foo.go", []byte("package foo\n\ntype Foo interface {\nDo() error\nGet() string\n}"), 0644)
+		require.Nil(t, err)
+		err = run([]string{"gomock", "regen", dir})
+		require.Nil(t, err)
+
+		b, err = os.ReadFile(dir + "/mock_test.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), `//gomock:args -f "my foo/foo.go"`+"\n")
+		assert.Contains(t, string(b), "func withFuncGet(")
+	})
+
+	t.Run("regenerate with fewer options", func(t *testing.T) {
+		dir := t.TempDir() + "/foo"
+		err := os.MkdirAll(dir, 0755)
+		require.Nil(t, err)
+		err = os.WriteFile(dir+"/go.mod", []byte("module example.com/foo\n\ngo 1.22\n"), 0644)
+		require.Nil(t, err)
+		err = os.WriteFile(dir+"/foo.go", []byte("package foo\n\nimport \"context\"\n\ntype Foo interface {\nDo(ctx context.Context) error\n}"), 0644)
+		require.Nil(t, err)
+
+		err = run([]string{"gomock", "-f", dir + "/foo.go", "-o", dir + "/mock_test.go", "--local", "--record"})
+		require.Nil(t, err)
+		b, err := os.ReadFile(dir + "/mock_test.go")
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "package foo\n\nimport (\n\t\"context\"\n\t\"fmt\"\n\t\"slices\"\n\t\"sync\"\n)\n"))
+
+		b = []byte(strings.Replace(string(b), " --record\n", "\n", 1))
+		err = os.WriteFile(dir+"/mock_test.go", b, 0644)
+		require.Nil(t, err)
+		captureStdout(t, func() {
+			err := run([]string{"gomock", "check", dir})
+			assert.ErrorIs(t, err, errStale)
+		})
+
+		err = run([]string{"gomock", "regen", dir})
+		require.Nil(t, err)
+		b, err = os.ReadFile(dir + "/mock_test.go")
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "package foo\n\nimport (\n\t\"context\"\n)\n\n"+template.Notice))
+		captureStdout(t, func() {
+			err := run([]string{"gomock", "check", dir})
+			assert.Nil(t, err)
+		})
+
+		if _, err := exec.LookPath("go"); err != nil {
+			t.Skip("go command not found, the mock is not compiled")
+		}
+		cmd := exec.Command("go", "vet", ".")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		assert.Nil(t, err, string(out))
+	})
+}
+
+func TestSplitArgs(t *testing.T) {
+	for _, args := range [][]string{
+		{"-f", "foo.go", "--strict"},
+		{"-f", "my foo/foo.go", "-i", "Foo"},
+		{"--default-error", `fmt.Errorf("%s\t%q", "a b", 'c')`},
+		{"-f", "foo.go", "--name", ""},
+	} {
+		got, err := splitArgs(joinArgs(args))
+		require.Nil(t, err)
+		assert.Equal(t, args, got)
+	}
+
+	_, err := splitArgs(`-f "foo.go`)
+	assert.ErrorContains(t, err, "unterminated quoted argument")
+}
+
+func captureStdout(t *testing.T, f func()) string {
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.Nil(t, err)
+
+	os.Stdout = w
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	f()
+	_ = w.Close()
+	return readstr(r)
 }
 
 func readstr(f *os.File) string {
@@ -95,3 +412,277 @@ func readstr(f *os.File) string {
 	}
 	return string(b)
 }
+
+func TestCheck(t *testing.T) {
+	tmpdir := t.TempDir() + "/foo"
+	err := os.MkdirAll(tmpdir, 0755)
+	require.Nil(t, err)
+	srcfile := tmpdir + "/foo.go"
+	outfile := tmpdir + "/mocks_test.go"
+
+	err = os.WriteFile(srcfile, []byte("package foo\n\ntype Foo interface {\nDo() error\n}"), 0644)
+	require.Nil(t, err)
+	err = run([]string{"gomock", "-f", srcfile, "-o", outfile})
+	require.Nil(t, err)
+
+	t.Run("up to date", func(t *testing.T) {
+		err := run([]string{"gomock", "check", tmpdir})
+		assert.Nil(t, err)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		err := os.WriteFile(srcfile, []byte("package foo\n\ntype Foo interface {\nDo() error\nUndo() error\n}"), 0644)
+		require.Nil(t, err)
+		before, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+
+		stdout := os.Stdout
+		r, w, err := os.Pipe()
+		require.Nil(t, err)
+
+		os.Stdout = w
+		defer func() {
+			os.Stdout = stdout
+		}()
+
+		err = run([]string{"gomock", "check", tmpdir})
+		assert.ErrorIs(t, err, errStale)
+		_ = w.Close()
+
+		out := readstr(r)
+		assert.Contains(t, out, "--- a/"+outfile)
+		assert.Contains(t, out, "+func (m *mockFoo) Undo() error {")
+
+		after, err := os.ReadFile(outfile)
+		require.Nil(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("checked in mocks", func(t *testing.T) {
+		// regenerate with: go run . regen ./internal/...
+		err := run([]string{"gomock", "check", "./internal/..."})
+		assert.Nil(t, err)
+	})
+}
+
+func TestGenerateConfig(t *testing.T) {
+	tmpdir := t.TempDir() + "/foo"
+	err := os.MkdirAll(tmpdir+"/sub", 0755)
+	require.Nil(t, err)
+	err = os.WriteFile(tmpdir+"/go.mod", []byte("module foo\n"), 0644)
+	require.Nil(t, err)
+	err = os.WriteFile(tmpdir+"/foo.go", []byte("package foo\n\ntype Foo interface {\nDo() error\n}\ntype Bar interface {\nGet() string\n}"), 0644)
+	require.Nil(t, err)
+
+	t.Run("yaml with defaults", func(t *testing.T) {
+		cfg := `
+defaults:
+  source: foo.go
+  export: true
+  pkgs:
+    foo: foo2
+targets:
+  - interface: Foo
+    destination: foo_mock.go
+  - interface: Bar
+    destination: bar_mock.go
+    style: struct
+    export: false
+`
+		err := os.WriteFile(tmpdir+"/gomock.yaml", []byte(cfg), 0644)
+		require.Nil(t, err)
+
+		t.Chdir(tmpdir + "/sub")
+		err = run([]string{"gomock", "generate"})
+		require.Nil(t, err)
+
+		b, err := os.ReadFile(tmpdir + "/foo_mock.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "//gomock:args -f foo.go -i Foo -x --pkgs foo=foo2\n")
+		assert.Contains(t, string(b), "func NewMockFoo(")
+
+		b, err = os.ReadFile(tmpdir + "/bar_mock.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "//gomock:args -f foo.go -i Bar --struct --pkgs foo=foo2\n")
+		assert.Contains(t, string(b), "type mockBar struct")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		cfg := `{"targets": [{"source": "foo.go", "interface": "Bar", "destination": "bar_json.go", "disambiguate": true}]}`
+		err := os.WriteFile(tmpdir+"/.gomock.json", []byte(cfg), 0644)
+		require.Nil(t, err)
+
+		err = run([]string{"gomock", "generate", "--config", tmpdir + "/.gomock.json"})
+		require.Nil(t, err)
+
+		b, err := os.ReadFile(tmpdir + "/bar_json.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "func withFuncBarGet(")
+	})
+
+	t.Run("invalid style", func(t *testing.T) {
+		cfg := `{"targets": [{"source": "foo.go", "style": "fancy"}]}`
+		err := os.WriteFile(tmpdir+"/bad.json", []byte(cfg), 0644)
+		require.Nil(t, err)
+
+		err = run([]string{"gomock", "generate", "-c", tmpdir + "/bad.json"})
+		assert.ErrorContains(t, err, "invalid style: fancy")
+	})
+
+	t.Run("config not found", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		err := run([]string{"gomock", "generate"})
+		assert.ErrorIs(t, err, errNoConfig)
+	})
+}
+
+func TestGenerateAnnotated(t *testing.T) {
+	tmpdir := t.TempDir() + "/foo"
+	err := os.MkdirAll(tmpdir+"/bar", 0755)
+	require.Nil(t, err)
+
+	foo := `package foo
+
+//gomock:generate -o mocks_test.go --struct -x
+type Foo interface {
+	Get() string
+	//gomock:ignore
+	Set(v string)
+}
+
+type Skipped interface {
+	Do() error
+}
+`
+	bar := `package bar
+
+//gomock:generate -o ../bar_mock_test.go -d
+type Bar interface {
+	Do() error
+}
+`
+	err = os.WriteFile(tmpdir+"/foo.go", []byte(foo), 0644)
+	require.Nil(t, err)
+	err = os.WriteFile(tmpdir+"/bar/bar.go", []byte(bar), 0644)
+	require.Nil(t, err)
+
+	err = run([]string{"gomock", tmpdir + "/..."})
+	require.Nil(t, err)
+
+	b, err := os.ReadFile(tmpdir + "/mocks_test.go")
+	require.Nil(t, err)
+	out := string(b)
+	assert.Contains(t, out, "//gomock:args -f foo.go -i Foo -x --struct\n")
+	assert.Contains(t, out, "type MockFoo struct")
+	assert.Contains(t, out, `panic("MockFoo.Set: not mocked")`)
+	assert.NotContains(t, out, "Skipped")
+
+	b, err = os.ReadFile(tmpdir + "/bar_mock_test.go")
+	require.Nil(t, err)
+	assert.Contains(t, string(b), "//gomock:args -f bar/bar.go -i Bar -d\n")
+	assert.Contains(t, string(b), "func withFuncBarDo(")
+
+	t.Run("invalid directive", func(t *testing.T) {
+		err := os.WriteFile(tmpdir+"/bar/bar.go", []byte("package bar\n\n//gomock:generate --nope\ntype Bar interface {\nDo() error\n}\n"), 0644)
+		require.Nil(t, err)
+		err = run([]string{"gomock", tmpdir + "/bar"})
+		assert.ErrorContains(t, err, "invalid directive")
+	})
+}
+
+func TestGeneratePackages(t *testing.T) {
+	tmpdir := t.TempDir() + "/foo"
+	err := os.MkdirAll(tmpdir+"/internal/bar", 0755)
+	require.Nil(t, err)
+
+	foo := `package foo
+
+//gomock:generate -o annotated_test.go
+type Foo interface {
+	Get() string
+}
+
+type Store interface {
+	Get() string
+}
+
+//gomock:ignore
+type Ignored interface {
+	Get() string
+}
+
+type unexported interface {
+	Get() string
+}
+
+type Number interface {
+	~int | ~float64
+}
+`
+	bar := `package bar
+
+type Bar interface {
+	Do() error
+}
+`
+	err = os.WriteFile(tmpdir+"/foo.go", []byte(foo), 0644)
+	require.Nil(t, err)
+	err = os.WriteFile(tmpdir+"/internal/bar/bar.go", []byte(bar), 0644)
+	require.Nil(t, err)
+
+	t.Run("beside", func(t *testing.T) {
+		err := run([]string{"gomock", "--all", tmpdir + "/..."})
+		require.Nil(t, err)
+
+		b, err := os.ReadFile(tmpdir + "/annotated_test.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "func withFuncGet(")
+
+		b, err = os.ReadFile(tmpdir + "/mock_store_test.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "//gomock:args -f foo.go -i Store -d --local\n")
+		assert.Contains(t, string(b), "func newMockStore(opt ...mockStoreOption) Store {")
+
+		b, err = os.ReadFile(tmpdir + "/internal/bar/mock_bar_test.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "package bar\n")
+		assert.Contains(t, string(b), "func withFuncBarDo(")
+
+		for _, name := range []string{"mock_foo_test.go", "mock_ignored_test.go", "mock_unexported_test.go", "mock_number_test.go"} {
+			_, err = os.Stat(tmpdir + "/" + name)
+			assert.ErrorIs(t, err, os.ErrNotExist, name)
+		}
+	})
+
+	t.Run("mocks tree", func(t *testing.T) {
+		t.Chdir(tmpdir)
+		err := run([]string{"gomock", "--all", "--layout", "mocks", "./..."})
+		assert.EqualError(t, err, "foo.go: Store: source is not in a module, cannot import it")
+
+		err = os.WriteFile(tmpdir+"/go.mod", []byte("module example.com/foo\n\ngo 1.22\n"), 0644)
+		require.Nil(t, err)
+		err = run([]string{"gomock", "--all", "--layout", "mocks", "./..."})
+		require.Nil(t, err)
+
+		b, err := os.ReadFile(tmpdir + "/mocks/internal/bar/mock_bar.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "package bar\n\nimport (\n\t\"example.com/foo/internal/bar\"\n)\n")
+		assert.Contains(t, string(b), "//gomock:args -f ../../../internal/bar/bar.go -i Bar -x -d\n")
+		assert.Contains(t, string(b), "func NewMockBar(opt ...mockBarOption) bar.Bar {")
+
+		b, err = os.ReadFile(tmpdir + "/mocks/mock_store.go")
+		require.Nil(t, err)
+		assert.Contains(t, string(b), "package foo\n\nimport (\n\t\"example.com/foo\"\n)\n")
+
+		if _, err := exec.LookPath("go"); err != nil {
+			t.Skip("go command not found, the mocks are not compiled")
+		}
+		out, err := exec.Command("go", "build", "./mocks/...").CombinedOutput()
+		assert.Nil(t, err, string(out))
+	})
+
+	t.Run("invalid layout", func(t *testing.T) {
+		err := run([]string{"gomock", "--all", "--layout", "nope", tmpdir})
+		assert.EqualError(t, err, "invalid layout: nope")
+	})
+}
@@ -8,12 +8,27 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 var (
 	ErrNotFound = errors.New("source does not contain a suitable interface type")
 )
 
+const (
+	// GenerateDirective above an interface declaration holds the options to generate its mock with
+	GenerateDirective = "//gomock:generate"
+	// IgnoreDirective above an interface method excludes the method from the generated helpers.
+	// Above an interface declaration, it excludes the interface from package-wide generation
+	IgnoreDirective = "//gomock:ignore"
+)
+
+// Directive is a GenerateDirective found above an interface declaration.
+type Directive struct {
+	InterfaceName string
+	Args          []string
+}
+
 type MockData struct {
 	PackageName           string
 	InterfaceName         string
@@ -22,33 +37,102 @@ type MockData struct {
 	Components            []*ast.Field
 	ExternalComponents    []*ast.Field
 	InheritedMethodFields map[string][]*ast.Field
+	IgnoredMethodFields   []*ast.Field // methods annotated with IgnoreDirective, including inherited ones
 }
 
 func (md *MockData) Len() int {
-	return len(md.MethodFields) + len(md.Components) + len(md.ExternalComponents)
+	return len(md.MethodFields) + len(md.Components) + len(md.ExternalComponents) + len(md.IgnoredMethodFields)
 }
 
 // Parses srcFile, which must be a valid Go source, and extracts data needed to generate a mock implementation of target.
 // If target is empty, the mocked interface will be the first interface encountered in the Go file.
+// Methods annotated with IgnoreDirective are moved to IgnoredMethodFields.
 func Parse(srcFile string, src interface{}, target string) (*MockData, error) {
-	f, err := parser.ParseFile(token.NewFileSet(), srcFile, src, parser.DeclarationErrors)
+	f, err := parser.ParseFile(token.NewFileSet(), srcFile, src, parser.DeclarationErrors|parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse source: %w", err)
 	}
 
-	md := &MockData{}
-	md.PackageName = f.Name.Name
-
 	spec, err := GetInterfaceSpec(f, target)
 	if err != nil {
 		return nil, err
 	}
+	md, err := newMockData(f.Name.Name, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the interface contains any identifier, detect composition
+	if len(md.Components) > 0 || len(md.ExternalComponents) > 0 {
+		inheritedMethods, err := parseDirContent(md, filepath.Dir(srcFile))
+		if err != nil {
+			return nil, err
+		}
+		md.InheritedMethodFields = inheritedMethods
+		md.moveIgnoredInheritedMethods()
+	}
+
+	return md, nil
+}
+
+// Parses several Go source files together and extracts data needed to generate a mock implementation of target.
+// Unlike Parse, composed interfaces are resolved only within the given files, which may belong to different packages.
+// If target is empty, the mocked interface will be the first interface encountered in the first file that declares one.
+func ParseFiles(srcFiles []string, target string) (*MockData, error) {
+	fset := token.NewFileSet()
+	pkgs := make(map[string]*ast.Package)
+
+	var spec *ast.TypeSpec
+	var pkgName string
+	for _, srcFile := range srcFiles {
+		f, err := parser.ParseFile(fset, srcFile, nil, parser.DeclarationErrors|parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse source: %w", err)
+		}
+
+		pkg, ok := pkgs[f.Name.Name]
+		if !ok {
+			pkg = &ast.Package{Name: f.Name.Name, Files: make(map[string]*ast.File)}
+			pkgs[f.Name.Name] = pkg
+		}
+		pkg.Files[srcFile] = f
+
+		if spec != nil {
+			continue
+		}
+		interfaces, first := findInterfaces(f)
+		if target == "" {
+			spec = interfaces[first]
+		} else {
+			spec = interfaces[target]
+		}
+		pkgName = f.Name.Name
+	}
+	if spec == nil {
+		return nil, fmt.Errorf("%w: target not found", ErrNotFound)
+	}
+
+	md, err := newMockData(pkgName, spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(md.Components) > 0 || len(md.ExternalComponents) > 0 {
+		md.InheritedMethodFields = findInheritedMethodFields(md, pkgs)
+		md.moveIgnoredInheritedMethods()
+	}
+	return md, nil
+}
+
+// Extracts the type parameters, methods and components of the interface declared by spec.
+func newMockData(pkgName string, spec *ast.TypeSpec) (*MockData, error) {
 	interfaceType := spec.Type.(*ast.InterfaceType)
 
 	if interfaceType.Incomplete {
 		return nil, errors.New("source interface declares no methods")
 	}
 
+	md := &MockData{}
+	md.PackageName = pkgName
 	md.InterfaceName = spec.Name.Name
 
 	if spec.TypeParams != nil {
@@ -60,6 +144,10 @@ func Parse(srcFile string, src interface{}, target string) (*MockData, error) {
 	for _, field := range interfaceType.Methods.List {
 		switch field.Type.(type) {
 		case *ast.FuncType:
+			if isIgnored(field) {
+				md.IgnoredMethodFields = append(md.IgnoredMethodFields, field)
+				continue
+			}
 			md.MethodFields = append(md.MethodFields, field)
 
 		case *ast.Ident:
@@ -69,17 +157,140 @@ func Parse(srcFile string, src interface{}, target string) (*MockData, error) {
 			md.ExternalComponents = append(md.ExternalComponents, field)
 		}
 	}
+	return md, nil
+}
 
-	// If the interface contains any identifier, detect composition
-	if len(md.Components) > 0 || len(md.ExternalComponents) > 0 {
-		inheritedMethods, err := parseDirContent(md, filepath.Dir(srcFile))
-		if err != nil {
-			return nil, err
+// Finds the interfaces in srcFile that are annotated with GenerateDirective, in declaration order.
+func FindDirectives(srcFile string, src interface{}) ([]Directive, error) {
+	decls, err := parseInterfaceDecls(srcFile, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var directives []Directive
+	for _, d := range decls {
+		if args, ok := directiveArgs(d.doc, GenerateDirective); ok {
+			directives = append(directives, Directive{d.spec.Name.Name, args})
 		}
-		md.InheritedMethodFields = inheritedMethods
 	}
+	return directives, nil
+}
 
-	return md, nil
+// Finds the exported interfaces in srcFile that can be mocked, in declaration order.
+// Constraint interfaces with type terms, interfaces without methods and interfaces annotated
+// with IgnoreDirective are skipped.
+func FindExported(srcFile string, src interface{}) ([]string, error) {
+	decls, err := parseInterfaceDecls(srcFile, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, d := range decls {
+		if !d.spec.Name.IsExported() {
+			continue
+		}
+		if _, ok := directiveArgs(d.doc, IgnoreDirective); ok {
+			continue
+		}
+		if isMockable(d.spec.Type.(*ast.InterfaceType)) {
+			names = append(names, d.spec.Name.Name)
+		}
+	}
+	return names, nil
+}
+
+type interfaceDecl struct {
+	spec *ast.TypeSpec
+	doc  *ast.CommentGroup
+}
+
+// Parses srcFile with comments and returns its interface declarations with their doc comments.
+func parseInterfaceDecls(srcFile string, src interface{}) ([]interfaceDecl, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), srcFile, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse source: %w", err)
+	}
+
+	var decls []interfaceDecl
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, s := range gd.Specs {
+			spec := s.(*ast.TypeSpec)
+			if _, ok := spec.Type.(*ast.InterfaceType); !ok {
+				continue
+			}
+			doc := spec.Doc
+			if doc == nil && len(gd.Specs) == 1 {
+				doc = gd.Doc
+			}
+			decls = append(decls, interfaceDecl{spec, doc})
+		}
+	}
+	return decls, nil
+}
+
+func isMockable(it *ast.InterfaceType) bool {
+	if it.Methods == nil || len(it.Methods.List) == 0 {
+		return false
+	}
+	for _, field := range it.Methods.List {
+		switch field.Type.(type) {
+		case *ast.FuncType, *ast.Ident, *ast.SelectorExpr:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the arguments of the given directive, if the comment group contains it.
+func directiveArgs(doc *ast.CommentGroup, directive string) ([]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+	for _, c := range doc.List {
+		rest, ok := strings.CutPrefix(c.Text, directive)
+		if ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Fields(rest), true
+		}
+	}
+	return nil, false
+}
+
+// Moves the ignored inherited methods to IgnoredMethodFields, in the order of the components.
+func (md *MockData) moveIgnoredInheritedMethods() {
+	names := make([]string, 0, len(md.Components)+len(md.ExternalComponents))
+	for _, field := range md.Components {
+		names = append(names, field.Type.(*ast.Ident).Name)
+	}
+	for _, field := range md.ExternalComponents {
+		names = append(names, field.Type.(*ast.SelectorExpr).Sel.Name)
+	}
+
+	for _, name := range names {
+		fields, ok := md.InheritedMethodFields[name]
+		if !ok {
+			continue
+		}
+		kept := make([]*ast.Field, 0, len(fields))
+		for _, field := range fields {
+			if isIgnored(field) {
+				md.IgnoredMethodFields = append(md.IgnoredMethodFields, field)
+				continue
+			}
+			kept = append(kept, field)
+		}
+		md.InheritedMethodFields[name] = kept
+	}
+}
+
+func isIgnored(field *ast.Field) bool {
+	_, ok := directiveArgs(field.Doc, IgnoreDirective)
+	return ok
 }
 
 // Finds the typespect of the target interface within the given AST file.
@@ -114,7 +325,7 @@ func GetInterfaceSpec(f *ast.File, target string) (*ast.TypeSpec, error) {
 }
 
 func parseDirContent(md *MockData, srcDir string) (map[string][]*ast.Field, error) {
-	pkgs, err := parser.ParseDir(token.NewFileSet(), srcDir, nil, parser.DeclarationErrors)
+	pkgs, err := parser.ParseDir(token.NewFileSet(), srcDir, nil, parser.DeclarationErrors|parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
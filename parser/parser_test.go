@@ -104,6 +104,26 @@ type TestInterface[T any] interface {
 		assert.Len(t, md.MethodFields, 1)
 	})
 
+	t.Run("ignored methods", func(t *testing.T) {
+		const src = `
+package test
+type TestInterface interface {
+	Get() string
+	//gomock:ignore
+	Set(v string)
+	// Delete removes everything.
+	//gomock:ignore
+	Delete()
+}`
+		md, err := Parse("", src, "")
+		require.Nil(t, err)
+		assert.Len(t, md.MethodFields, 1)
+		assert.Len(t, md.IgnoredMethodFields, 2)
+		assert.Equal(t, "Set", md.IgnoredMethodFields[0].Names[0].Name)
+		assert.Equal(t, "Delete", md.IgnoredMethodFields[1].Names[0].Name)
+		assert.Equal(t, 3, md.Len())
+	})
+
 	t.Run("find all interfaces", func(t *testing.T) {
 		pkg := &ast.Package{
 			Name:    "",
@@ -187,3 +207,112 @@ func TestParseDirContent(t *testing.T) {
 	assert.Equal(t, "RootMethod", result["RootInterface"][0].Names[0].Name)
 	assert.Equal(t, "SubMethod", result["SubInterface"][0].Names[0].Name)
 }
+
+func TestFindDirectives(t *testing.T) {
+	const src = `
+package test
+
+//gomock:generate -o mocks_test.go --struct -x
+type Foo interface {
+	Get() string
+}
+
+type Bar interface {
+	Do() error
+}
+
+type (
+	// Baz does things.
+	//gomock:generate
+	Baz interface {
+		Do() error
+	}
+)
+
+//gomock:generated
+type Qux interface {
+	Do() error
+}
+`
+	directives, err := FindDirectives("", src)
+	require.Nil(t, err)
+	assert.Equal(t, []Directive{
+		{"Foo", []string{"-o", "mocks_test.go", "--struct", "-x"}},
+		{"Baz", []string{}},
+	}, directives)
+
+	_, err = FindDirectives("", "gibberish")
+	assert.ErrorContains(t, err, "cannot parse source")
+}
+
+func TestFindExported(t *testing.T) {
+	const src = `
+package test
+
+type Foo interface {
+	Get() string
+}
+
+type bar interface {
+	Do() error
+}
+
+//gomock:ignore
+type Baz interface {
+	Do() error
+}
+
+type Empty interface{}
+
+type Number interface {
+	~int | ~float64
+}
+
+type Composed interface {
+	Foo
+	io.Reader
+}
+`
+	names, err := FindExported("", src)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"Foo", "Composed"}, names)
+}
+
+func TestParseFiles(t *testing.T) {
+	tmp := t.TempDir()
+	files := map[string]string{
+		"foo.go": "package foo\n\nimport \"bar\"\n\ntype Foo interface {\n\tbaz\n\tbar.Bar\n\tGet() string\n}\n",
+		"baz.go": "package foo\n\ntype baz interface {\n\tDo() error\n\t//gomock:ignore\n\tUndo() error\n}\n",
+		"bar.go": "package bar\n\ntype Bar interface {\n\tSet(v string)\n}\n",
+	}
+	for name, src := range files {
+		err := os.WriteFile(filepath.Join(tmp, name), []byte(src), 0644)
+		require.Nil(t, err)
+	}
+
+	t.Run("composed across files", func(t *testing.T) {
+		md, err := ParseFiles([]string{filepath.Join(tmp, "baz.go"), filepath.Join(tmp, "foo.go"), filepath.Join(tmp, "bar.go")}, "Foo")
+		require.Nil(t, err)
+		assert.Equal(t, "foo", md.PackageName)
+		assert.Equal(t, "Foo", md.InterfaceName)
+		assert.Equal(t, "Do", md.InheritedMethodFields["baz"][0].Names[0].Name)
+		assert.Equal(t, "Set", md.InheritedMethodFields["Bar"][0].Names[0].Name)
+		assert.Equal(t, "Undo", md.IgnoredMethodFields[0].Names[0].Name)
+	})
+
+	t.Run("default to first", func(t *testing.T) {
+		md, err := ParseFiles([]string{filepath.Join(tmp, "bar.go"), filepath.Join(tmp, "foo.go")}, "")
+		require.Nil(t, err)
+		assert.Equal(t, "Bar", md.InterfaceName)
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		_, err := ParseFiles([]string{filepath.Join(tmp, "bar.go")}, "Foo")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		_, err := ParseFiles([]string{filepath.Join(tmp, "missing.go")}, "")
+		assert.ErrorContains(t, err, "cannot parse source")
+	})
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vibridi/gomock/v3/writer/template"
+)
+
+// Regenerates every mock file matched by patterns, using the arguments recorded in the file.
+// Files generated without recorded arguments are skipped.
+func regen(patterns []string) error {
+	files, err := goFiles(patterns)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		o, err := recordedOptions(file)
+		if err != nil {
+			return err
+		}
+		if o == nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "regenerating %s\n", file)
+
+		if err := generate(o); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// Returns the options recorded in a generated file, with the source resolved relative to the file
+// and the file itself as destination, or nil if the file has no recorded arguments.
+func recordedOptions(file string) (*options, error) {
+	args, err := recordedArgs(file)
+	if err != nil || args == nil {
+		return nil, err
+	}
+
+	o, err := parseArgs(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid recorded arguments: %w", file, err)
+	}
+	for i, src := range o.sourceFiles {
+		o.sourceFiles[i] = resolve(filepath.Dir(file), src)
+	}
+	o.destination = file
+	return o, nil
+}
+
+// Returns the arguments recorded below the notice of a generated file, or nil if there are none.
+func recordedArgs(file string) ([]string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(b, []byte(template.Notice)) {
+		return nil, nil
+	}
+
+	notice := false
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == template.Notice {
+			notice = true
+			continue
+		}
+		if notice && strings.HasPrefix(line, template.ArgsDirective+" ") {
+			args, err := splitArgs(strings.TrimPrefix(line, template.ArgsDirective))
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid recorded arguments: %w", file, err)
+			}
+			return args, nil
+		}
+	}
+	return nil, sc.Err()
+}
+
+// Joins the arguments with spaces, quoting those that contain spaces or that wouldn't
+// read back as they are, so that splitArgs returns the original arguments.
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if q := strconv.Quote(a); a == "" || strings.ContainsAny(a, " \t") || q[1:len(q)-1] != a {
+			a = q
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Splits a line of arguments joined by joinArgs. Arguments are separated by spaces and
+// an argument that begins with a double quote is a quoted Go string.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return args, nil
+		}
+		if line[0] != '"' {
+			n := strings.IndexAny(line, " \t")
+			if n < 0 {
+				n = len(line)
+			}
+			args = append(args, line[:n])
+			line = line[n:]
+			continue
+		}
+		q, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("unterminated quoted argument %s", line)
+		}
+		arg, err := strconv.Unquote(q)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		line = line[len(q):]
+	}
+}
+
+// Expands the patterns into a list of Go files. A pattern can be a file, a directory,
+// or a directory followed by "/..." to include all its sub-directories. Like the go tool,
+// directories whose name begins with "." or "_" and testdata directories are ignored
+// while walking. The default pattern is "./...".
+func goFiles(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	var files []string
+	for _, p := range patterns {
+		root, recursive := strings.CutSuffix(p, "/...")
+		if root == "" {
+			root = "."
+		}
+
+		fi, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, root)
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path == root {
+					return nil
+				}
+				if !recursive || strings.HasPrefix(d.Name(), ".") || strings.HasPrefix(d.Name(), "_") || d.Name() == "testdata" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -1,45 +1,51 @@
 package writer
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"io"
+	"io/fs"
 	"os"
 	"strings"
 
 	"github.com/vibridi/gomock/v3/writer/template"
 )
 
+// Writes text below the gomock notice of the destination file, creating the file if it doesn't exist.
 func File(destination string, pkg string, text []byte) error {
-	dst, err := os.OpenFile(destination, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
+	src, err := os.ReadFile(destination)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
-	defer dst.Close()
 
-	fi, err := dst.Stat()
+	b, err := Content(destination, pkg, src, text)
 	if err != nil {
 		return err
 	}
+	return os.WriteFile(destination, b, 0644)
+}
+
+// Returns the content that File would write to destination, given its current content src.
+// Import declarations at the top of text are merged into the imports of the destination file.
+func Content(destination string, pkg string, src []byte, text []byte) ([]byte, error) {
+	imports, text := splitImports(text)
 
 	pos := 0
 	pad := 0
-	if fi.Size() > 0 {
-		b, err := io.ReadAll(dst)
+	if len(src) > 0 {
+		var err error
+		pos, pad, err = getWritePos(src)
 		if err != nil {
-			return err
-		}
-		pos, pad, err = getWritePos(b)
-		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	var head []byte
 	if pos > 0 {
-		dst.Truncate(int64(pos))
-		dst.Seek(0, io.SeekEnd)
+		head = src[:pos]
 	} else {
 		s := strings.Split(destination, "/")
 		if pkg == "" {
@@ -50,17 +56,37 @@ func File(destination string, pkg string, text []byte) error {
 			}
 		}
 
-		dst.WriteString("package " + pkg)
+		head = []byte("package " + pkg)
 		pad = 2
 	}
 
+	// the imports of the code previously written below the notice may no longer be needed
+	if pos > 0 && pad == 0 {
+		var err error
+		head, err = pruneImports(head, src[pos:], text)
+		if err != nil {
+			return nil, fmt.Errorf("prune imports error: %w", err)
+		}
+	}
+
+	head, err := addImports(head, imports)
+	if err != nil {
+		return nil, fmt.Errorf("add imports error: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(head)
 	for range pad {
-		dst.WriteString("\n")
+		buf.WriteString("\n")
+	}
+	buf.WriteString(template.Notice)
+	buf.WriteString("\n\n")
+	buf.Write(text)
+	// end the file with a newline, as gofmt does
+	if !bytes.HasSuffix(text, []byte("\n")) {
+		buf.WriteString("\n")
 	}
-	dst.WriteString(template.Notice)
-	dst.WriteString("\n\n")
-	dst.Write(text)
-	return err
+	return buf.Bytes(), nil
 }
 
 // returns the file offset where to start writing and the number of newlines to insert before writing
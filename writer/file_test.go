@@ -27,7 +27,7 @@ func TestWriteFile(t *testing.T) {
 		require.Nil(t, err)
 		b, err := os.ReadFile(tmpfile)
 		require.Nil(t, err)
-		assert.Equal(t, "package foo\n\n// generated by gomock: do not edit below this line\n\nfunc foo() {}", string(b))
+		assert.Equal(t, "package foo\n\n// generated by gomock: do not edit below this line\n\nfunc foo() {}\n", string(b))
 	})
 
 	t.Run("file does not exist no package", func(t *testing.T) {
@@ -35,7 +35,7 @@ func TestWriteFile(t *testing.T) {
 		require.Nil(t, err)
 		b, err := os.ReadFile(tmpfile)
 		require.Nil(t, err)
-		assert.Equal(t, "package foo\n\n// generated by gomock: do not edit below this line\n\nfunc foo() {}", string(b))
+		assert.Equal(t, "package foo\n\n// generated by gomock: do not edit below this line\n\nfunc foo() {}\n", string(b))
 	})
 
 	t.Run("file does not exist default main", func(t *testing.T) {
@@ -46,7 +46,7 @@ func TestWriteFile(t *testing.T) {
 		defer func() {
 			_ = os.Remove("foo.go")
 		}()
-		assert.Equal(t, "package main\n\n// generated by gomock: do not edit below this line\n\nfunc foo() {}", string(b))
+		assert.Equal(t, "package main\n\n// generated by gomock: do not edit below this line\n\nfunc foo() {}\n", string(b))
 	})
 
 	t.Run("parse error", func(t *testing.T) {
@@ -82,7 +82,8 @@ package blah
 
 // generated by gomock: do not edit below this line
 
-func foo() {}`
+func foo() {}
+`
 		assert.Equal(t, want, string(b))
 	})
 
@@ -114,7 +115,8 @@ import (
 
 // generated by gomock: do not edit below this line
 
-func foo() {}`
+func foo() {}
+`
 		assert.Equal(t, want, string(b))
 	})
 
@@ -154,7 +156,8 @@ import (
 
 // generated by gomock: do not edit below this line
 
-func foo() {}`
+func foo() {}
+`
 		assert.Equal(t, want, string(b))
 	})
 
@@ -176,7 +179,8 @@ package main
 
 // generated by gomock: do not edit below this line
 
-func foo() {}`
+func foo() {}
+`
 		assert.Equal(t, want, string(b))
 	})
 
@@ -197,7 +201,8 @@ package main
 
 // generated by gomock: do not edit below this line
 
-func foo() {}`
+func foo() {}
+`
 		assert.Equal(t, want, string(b))
 
 		data = []byte("func foo() {}\nfunc bar() {}")
@@ -211,8 +216,108 @@ package main
 // generated by gomock: do not edit below this line
 
 func foo() {}
-func bar() {}`
+func bar() {}
+`
+		assert.Equal(t, want, string(b))
+
+	})
+}
+
+func TestContent(t *testing.T) {
+	src := []byte("package foo\n\nimport \"fmt\"\n\n" + template.Notice + "\n\nfunc old() {}")
+
+	b, err := Content("foo/bar.go", "", src, []byte("func foo() {}"))
+	require.Nil(t, err)
+	assert.Equal(t, "package foo\n\nimport \"fmt\"\n\n"+template.Notice+"\n\nfunc foo() {}\n", string(b))
+
+	b, err = Content("foo/bar.go", "", nil, []byte("func foo() {}"))
+	require.Nil(t, err)
+	assert.Equal(t, "package foo\n\n"+template.Notice+"\n\nfunc foo() {}\n", string(b))
+}
+
+func TestContentImports(t *testing.T) {
+	text := []byte("//gomock:args -f foo.go\n\nimport (\n\t\"sync\"\n\t\"testing\"\n)\n\nfunc foo() {}")
+
+	t.Run("new file", func(t *testing.T) {
+		b, err := Content("foo/bar.go", "", nil, text)
+		require.Nil(t, err)
+		want := "package foo\n\nimport (\n\t\"sync\"\n\t\"testing\"\n)\n\n" + template.Notice + "\n\n//gomock:args -f foo.go\n\nfunc foo() {}\n"
 		assert.Equal(t, want, string(b))
+	})
 
+	t.Run("merge into import block", func(t *testing.T) {
+		src := "package foo\n\nimport (\n\t\"testing\"\n)\n\n" + template.Notice + "\n\nfunc old() {}"
+		b, err := Content("foo/bar.go", "", []byte(src), text)
+		require.Nil(t, err)
+		want := "package foo\n\nimport (\n\t\"sync\"\n\t\"testing\"\n)\n\n" + template.Notice + "\n\n//gomock:args -f foo.go\n\nfunc foo() {}\n"
+		assert.Equal(t, want, string(b))
+
+		// writing again doesn't change the file
+		b2, err := Content("foo/bar.go", "", b, text)
+		require.Nil(t, err)
+		assert.Equal(t, string(b), string(b2))
+	})
+
+	t.Run("after single import", func(t *testing.T) {
+		src := "package foo\n\nimport \"fmt\"\n\nfunc main() {}\n"
+		b, err := Content("foo/bar.go", "", []byte(src), text)
+		require.Nil(t, err)
+		want := "package foo\n\nimport \"fmt\"\nimport \"sync\"\nimport \"testing\"\n\n" + template.Notice + "\n\n//gomock:args -f foo.go\n\nfunc foo() {}\n"
+		assert.Equal(t, want, string(b))
+	})
+
+	t.Run("merge into import groups", func(t *testing.T) {
+		text := []byte("import (\n\t\"context\"\n\t\"sync\"\n\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\nfunc foo() {}")
+		src := "package foo\n\nimport (\n\t\"fmt\" // comment\n\t\"testing\"\n\n\t\"github.com/stretchr/testify/assert\"\n)\n\n" + template.Notice + "\n\nfunc old() {}"
+		b, err := Content("foo/bar.go", "", []byte(src), text)
+		require.Nil(t, err)
+		want := "package foo\n\nimport (\n\t\"context\"\n\t\"fmt\" // comment\n\t\"sync\"\n\t\"testing\"\n\n\t\"github.com/stretchr/testify/assert\"\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\n" + template.Notice + "\n\nfunc foo() {}\n"
+		assert.Equal(t, want, string(b))
+	})
+
+	t.Run("new import groups", func(t *testing.T) {
+		text := []byte("import (\n\t\"sync\"\n\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\nfunc foo() {}")
+		src := "package foo\n\nimport (\n\t\"example.com/bar\"\n)\n\n" + template.Notice
+		b, err := Content("foo/bar.go", "", []byte(src), text)
+		require.Nil(t, err)
+		want := "package foo\n\nimport (\n\t\"sync\"\n\n\t\"example.com/bar\"\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\n" + template.Notice + "\n\nfunc foo() {}\n"
+		assert.Equal(t, want, string(b))
+	})
+
+	t.Run("drop imports no longer used", func(t *testing.T) {
+		src := "package foo\n\nimport (\n\t\"context\"\n\t\"os\"\n\t\"sync\"\n\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\n" +
+			"var _ = os.Args\n\n" + template.Notice + "\n\nvar mu sync.Mutex\nvar c *expect.Controller\nvar ctx context.Context\n"
+		text := []byte("import (\n\t\"context\"\n)\n\nvar ctx context.Context")
+		b, err := Content("foo/bar.go", "", []byte(src), text)
+		require.Nil(t, err)
+		want := "package foo\n\nimport (\n\t\"context\"\n\t\"os\"\n)\n\nvar _ = os.Args\n\n" + template.Notice + "\n\nvar ctx context.Context\n"
+		assert.Equal(t, want, string(b))
+
+		// without imports left, the declaration is removed
+		b, err = Content("foo/bar.go", "", b, []byte("var x int"))
+		require.Nil(t, err)
+		want = "package foo\n\nimport (\n\t\"os\"\n)\n\nvar _ = os.Args\n\n" + template.Notice + "\n\nvar x int\n"
+		assert.Equal(t, want, string(b))
+
+		src = "package foo\n\nimport (\n\t\"sync\"\n)\n\n" + template.Notice + "\n\nvar mu sync.Mutex\n"
+		b, err = Content("foo/bar.go", "", []byte(src), []byte("var x int"))
+		require.Nil(t, err)
+		assert.Equal(t, "package foo\n\n"+template.Notice+"\n\nvar x int\n", string(b))
+	})
+
+	t.Run("keep imports not used by the generated code", func(t *testing.T) {
+		// blank imports, and imports whose name can't be told from the path
+		src := "package foo\n\nimport (\n\t_ \"embed\"\n\t\"gopkg.in/yaml.v3\"\n)\n\n" + template.Notice + "\n\nvar _ = yaml.Marshal\n"
+		b, err := Content("foo/bar.go", "", []byte(src), []byte("var x int"))
+		require.Nil(t, err)
+		assert.Equal(t, "package foo\n\nimport (\n\t_ \"embed\"\n\t\"gopkg.in/yaml.v3\"\n)\n\n"+template.Notice+"\n\nvar x int\n", string(b))
+	})
+
+	t.Run("grouped imports", func(t *testing.T) {
+		text := []byte("import (\n\t\"sync\"\n\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\nfunc foo() {}")
+		b, err := Content("foo/bar.go", "", nil, text)
+		require.Nil(t, err)
+		want := "package foo\n\nimport (\n\t\"sync\"\n\n\t\"github.com/vibridi/gomock/v3/expect\"\n)\n\n" + template.Notice + "\n\nfunc foo() {}\n"
+		assert.Equal(t, want, string(b))
 	})
 }
@@ -0,0 +1,351 @@
+package writer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Separates the import declarations at the top of the generated text from the rest of the code.
+// Comments that precede the imports are kept. If text can't be parsed, it is returned unchanged.
+func splitImports(text []byte) ([]*ast.ImportSpec, []byte) {
+	const prefix = "package p\n"
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", append([]byte(prefix), text...), parser.ImportsOnly)
+	if err != nil || len(f.Imports) == 0 {
+		return nil, text
+	}
+
+	start := fset.Position(f.Decls[0].Pos()).Offset - len(prefix)
+	end := fset.Position(f.Decls[len(f.Decls)-1].End()).Offset - len(prefix)
+
+	rest := make([]byte, 0, len(text)-end+start)
+	rest = append(rest, text[:start]...)
+	rest = append(rest, bytes.TrimLeft(text[end:], "\n")...)
+	return f.Imports, rest
+}
+
+// Adds to the head of a Go file the imports that it doesn't declare yet. The imports are added to
+// the last import declaration, or right after the package clause if there are none.
+func addImports(head []byte, imports []*ast.ImportSpec) ([]byte, error) {
+	if len(imports) == 0 {
+		return head, nil
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", head, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(f.Imports))
+	for _, spec := range f.Imports {
+		existing[importString(spec)] = true
+	}
+
+	var missing []string
+	for _, spec := range imports {
+		if s := importString(spec); !existing[s] {
+			missing = append(missing, s)
+			existing[s] = true
+		}
+	}
+	if len(missing) == 0 {
+		return head, nil
+	}
+
+	var last *ast.GenDecl
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			last = gen
+		}
+	}
+
+	var (
+		buf bytes.Buffer
+		pos int
+	)
+	switch {
+	case last != nil && last.Lparen.IsValid():
+		return insertImports(fset, head, last, missing), nil
+
+	case last != nil:
+		pos = fset.Position(last.End()).Offset
+		for _, s := range missing {
+			buf.WriteString("\nimport " + s)
+		}
+
+	default:
+		pos = fset.Position(f.Name.End()).Offset
+		buf.WriteString("\n\nimport (\n")
+		for i, s := range missing {
+			// standard library packages are grouped apart from the others
+			if i > 0 && isStd(missing[i-1]) && !isStd(s) {
+				buf.WriteString("\n")
+			}
+			buf.WriteString("\t" + s + "\n")
+		}
+		buf.WriteString(")")
+	}
+
+	out := make([]byte, 0, len(head)+buf.Len())
+	out = append(out, head[:pos]...)
+	out = append(out, buf.Bytes()...)
+	out = append(out, head[pos:]...)
+	return out, nil
+}
+
+// Removes from the head of a Go file the imports that the previously generated code used and that
+// neither the head nor the new generated code use anymore. Imports whose name can't be told from
+// their path, and blank and dot imports, are kept.
+func pruneImports(head, oldText, newText []byte) ([]byte, error) {
+	old := qualifiers(oldText)
+	if len(old) == 0 {
+		return head, nil
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", head, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := qualifiers(newText)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	var unused []*ast.ImportSpec
+	for _, spec := range f.Imports {
+		if name := importName(spec); name != "" && old[name] && !used[name] {
+			unused = append(unused, spec)
+		}
+	}
+	if len(unused) == 0 {
+		return head, nil
+	}
+	return removeImports(fset, head, f, unused), nil
+}
+
+// Returns the identifiers that qualify selector expressions in the declarations of text,
+// e.g. sync in sync.Mutex. It returns nil if text can't be parsed.
+func qualifiers(text []byte) map[string]bool {
+	f, err := parser.ParseFile(token.NewFileSet(), "", append([]byte("package p\n"), text...), 0)
+	if err != nil {
+		return nil
+	}
+	names := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				names[id.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// Returns the name that the import spec declares in the file, or an empty string if it is a blank or dot
+// import, or if the name isn't given and can't be told from the path, e.g. gopkg.in/yaml.v3.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return ""
+		}
+		return spec.Name.Name
+	}
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		return ""
+	}
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	// major version suffixes, e.g. github.com/vibridi/gomock/v3
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	if !token.IsIdentifier(name) {
+		return ""
+	}
+	return name
+}
+
+// Removes the import specs from the head of a Go file, together with the import declarations that they
+// leave empty. In parenthesized declarations, the blank lines left by groups that become empty are dropped.
+func removeImports(fset *token.FileSet, head []byte, f *ast.File, specs []*ast.ImportSpec) []byte {
+	type edit struct {
+		start, end int
+		text       []byte
+	}
+	var edits []edit
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		removed := make(map[int]bool) // lines of the removed specs
+		n := 0
+		for _, s := range gen.Specs {
+			if slices.Contains(specs, s.(*ast.ImportSpec)) {
+				for l := fset.Position(s.Pos()).Line; l <= fset.Position(s.End()).Line; l++ {
+					removed[l] = true
+				}
+				n++
+			}
+		}
+		if n == 0 {
+			continue
+		}
+
+		if n == len(gen.Specs) {
+			// the declaration and the blank lines that follow it
+			end := offset(gen.End())
+			for end < len(head) && head[end] == '\n' {
+				end++
+			}
+			edits = append(edits, edit{offset(lineStart(fset, gen.Pos())), end, nil})
+			continue
+		}
+
+		start := fset.Position(gen.Lparen).Line + 1
+		stop := fset.Position(gen.Rparen).Line
+		if start > stop {
+			// all the specs are on one line, which isn't worth rewriting
+			continue
+		}
+		var lines [][]byte
+		for l := start; l < stop; l++ {
+			line := head[offset(fset.File(gen.Pos()).LineStart(l)):offset(fset.File(gen.Pos()).LineStart(l+1))]
+			blank := len(bytes.TrimSpace(line)) == 0
+			switch {
+			case removed[l]:
+			case blank && (len(lines) == 0 || len(bytes.TrimSpace(lines[len(lines)-1])) == 0):
+			default:
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 && len(bytes.TrimSpace(lines[len(lines)-1])) == 0 {
+			lines = lines[:len(lines)-1]
+		}
+		edits = append(edits, edit{offset(fset.File(gen.Pos()).LineStart(start)), offset(lineStart(fset, gen.Rparen)), bytes.Join(lines, nil)})
+	}
+
+	out := make([]byte, 0, len(head))
+	prev := 0
+	for _, e := range edits {
+		out = append(out, head[prev:e.start]...)
+		out = append(out, e.text...)
+		prev = e.end
+	}
+	return append(out, head[prev:]...)
+}
+
+// Inserts the imports into a parenthesized import declaration. Each import is added to the first group
+// of standard library packages or to the last group of other packages, before the first import that sorts
+// after it. A new group is started if there is no such group.
+func insertImports(fset *token.FileSet, head []byte, decl *ast.GenDecl, imports []string) []byte {
+	// groups of consecutive import specs, separated by blank lines
+	var groups [][]*ast.ImportSpec
+	prevLine := 0
+	for _, spec := range decl.Specs {
+		line := fset.Position(spec.Pos()).Line
+		if len(groups) == 0 || line > prevLine+1 {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], spec.(*ast.ImportSpec))
+		prevLine = fset.Position(spec.End()).Line
+	}
+
+	edits := make(map[int]string)
+	var offsets []int
+	insert := func(pos token.Pos, text string) {
+		off := fset.Position(pos).Offset
+		if _, ok := edits[off]; !ok {
+			offsets = append(offsets, off)
+		}
+		edits[off] += text
+	}
+
+	for _, s := range imports {
+		var group []*ast.ImportSpec
+		for _, g := range groups {
+			std := isStd(importString(g[0]))
+			if std && isStd(s) {
+				group = g
+				break
+			}
+			if !std && !isStd(s) {
+				group = g
+			}
+		}
+
+		switch {
+		case group != nil:
+			last := fset.Position(group[len(group)-1].End()).Line
+			pos := fset.File(decl.Pos()).LineStart(last + 1)
+			for _, spec := range group {
+				if importString(spec) > s {
+					pos = lineStart(fset, spec.Pos())
+					break
+				}
+			}
+			insert(pos, "\t"+s+"\n")
+
+		case isStd(s) && len(groups) > 0:
+			insert(lineStart(fset, groups[0][0].Pos()), "\t"+s+"\n\n")
+
+		case len(groups) > 0:
+			insert(decl.Rparen, "\n\t"+s+"\n")
+
+		default:
+			insert(decl.Rparen, "\t"+s+"\n")
+		}
+	}
+
+	slices.Sort(offsets)
+	out := make([]byte, 0, len(head)+len(imports)*32)
+	prev := 0
+	for _, off := range offsets {
+		out = append(out, head[prev:off]...)
+		out = append(out, edits[off]...)
+		prev = off
+	}
+	return append(out, head[prev:]...)
+}
+
+// Returns the position of the first character of the line that contains pos.
+func lineStart(fset *token.FileSet, pos token.Pos) token.Pos {
+	return fset.File(pos).LineStart(fset.Position(pos).Line)
+}
+
+// Returns the import spec as it appears in source, e.g. `foo "example.com/bar"`.
+func importString(spec *ast.ImportSpec) string {
+	path, err := strconv.Unquote(spec.Path.Value)
+	if err != nil {
+		path = spec.Path.Value
+	}
+	if spec.Name != nil {
+		return spec.Name.Name + " " + strconv.Quote(path)
+	}
+	return strconv.Quote(path)
+}
+
+// Reports whether the import spec refers to a standard library package, i.e. whose path doesn't begin with a domain name.
+func isStd(spec string) bool {
+	path := spec[strings.Index(spec, `"`)+1:]
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
@@ -3,30 +3,84 @@ package template
 import (
 	"go/ast"
 	"go/token"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/vibridi/gomock/v3/internal/fn"
 )
 
 // Holds the data needed to execute the mock template.
 type data struct {
-	Qualify       bool
-	Export        bool
-	Disambiguate  bool
-	Package       string
-	ServiceName   string
-	InterfaceName string
-	FuncDefs      []*funcDef
-	UnnamedSig    bool
-	Underlying    map[string]string
-	Aliases       map[string]string
-	PrefixPackage bool
-	TypeParamList string // full type parameter list as it appears in the interface declaration
-	TypeArguments string // type argument list as it appears in the method receiver
+	Qualify          bool
+	Export           bool
+	Disambiguate     bool
+	Package          string
+	ServiceName      string
+	InterfaceName    string
+	FuncDefs         []*funcDef
+	Stubs            []*funcDef // methods that are implemented but not mocked
+	UnnamedSig       bool
+	Underlying       map[string]string
+	Aliases          map[string]string
+	PrefixPackage    bool
+	Strict           bool
+	Delegate         bool
+	CannedReturns    bool
+	SequencedReturns bool
+	Record           bool
+	Expect           bool
+	Sequence         bool
+	Gates            bool
+	ContextAware     bool
+	Faults           bool
+	VerifyOverrides  bool
+	Trace            bool
+	Reset            bool
+	SafeDefaults     bool     // zero values of channels, functions and iterators are safe to use
+	DefaultError     string   // error returned by default implementations, see Opts.DefaultError
+	Locked           bool     // the mock has state that changes after construction, guarded by a mutex
+	ReturnsMock      bool     // the constructor returns the mock type instead of the interface
+	MockType         string   // name of the generated mock type
+	Imports          []string // paths of the packages imported by the generated code
+	TypeParamList    string   // full type parameter list as it appears in the interface declaration
+	TypeArguments    string   // type argument list as it appears in the method receiver
 
 	// unexported
 	typeParamSet map[string]struct{}
+	importNames  map[string]string // names of the imports whose package name differs from the last element of their path
+}
+
+// Adds a package path to the imports of the generated code, keeping them sorted.
+func (td *data) addImport(path string) {
+	if i, found := slices.BinarySearch(td.Imports, path); !found {
+		td.Imports = slices.Insert(td.Imports, i, path)
+	}
+}
+
+// Returns the import specs of the generated code in two groups: the standard library packages first,
+// then the other packages. Empty groups are omitted.
+func (td *data) ImportGroups() [][]string {
+	var std, other []string
+	for _, path := range td.Imports {
+		spec := strconv.Quote(path)
+		if name, ok := td.importNames[path]; ok {
+			spec = name + " " + spec
+		}
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	var groups [][]string
+	for _, g := range [][]string{std, other} {
+		if len(g) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	return groups
 }
 
 // Populates TypeParamList and TypeArguments from the given list of type parameters.
@@ -54,13 +108,92 @@ func (td *data) AddTypeParameters(typeParams []*ast.Field) {
 }
 
 func (td *data) AppendFuncDef(field *ast.Field) {
+	if fd := td.newFuncDef(field); fd != nil {
+		td.FuncDefs = append(td.FuncDefs, fd)
+	}
+}
+
+// Appends a method that the mock implements only to satisfy the interface. Calling it panics.
+func (td *data) AppendStub(field *ast.Field) {
+	if fd := td.newFuncDef(field); fd != nil {
+		td.Stubs = append(td.Stubs, fd)
+	}
+}
+
+// Returns the name of the option helper of the given kind for the method, e.g. withFuncGet.
+func (td *data) HelperName(kind string, fd *funcDef) string {
+	return td.OptionName(kind) + fd.Name
+}
+
+// Returns the name of the option of the given kind, e.g. withRecorder.
+// Options are exported with Export, and include the service name with Disambiguate.
+func (td *data) OptionName(kind string) string {
+	name := "with" + kind
+	if td.Export {
+		name = "W" + name[1:]
+	}
+	if td.Disambiguate {
+		name += td.ServiceName
+	}
+	return name
+}
+
+// Returns the mocked interface type, qualified with its package unless Qualify is false, e.g. foo.Store[T].
+func (td *data) InterfaceType() string {
+	name := td.InterfaceName + td.TypeArguments
+	if td.Qualify {
+		name = td.Package + "." + name
+	}
+	return name
+}
+
+// Reports whether any of the mocked methods returns values.
+func (td *data) HasResults() bool {
+	return slices.ContainsFunc(td.FuncDefs, func(fd *funcDef) bool {
+		return len(fd.Results) > 0
+	})
+}
+
+// Reports whether the interface has a method with the given name, mocked or not.
+func (td *data) hasMethod(name string) bool {
+	for _, fd := range slices.Concat(td.FuncDefs, td.Stubs) {
+		if fd.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether name is declared by the body of the generated methods: the receiver, the locals
+// read under the lock, and the start time of traced calls.
+func (td *data) isLocal(name string) bool {
+	switch name {
+	case "m":
+		return true
+	case "f":
+		return td.Locked
+	case "g":
+		return td.Gates
+	case "inj":
+		return td.Locked && td.Faults
+	case "tr":
+		return td.Locked && td.Trace
+	case "start":
+		return td.Trace
+	}
+	return false
+}
+
+func (td *data) newFuncDef(field *ast.Field) *funcDef {
 	ftype, ok := field.Type.(*ast.FuncType)
 	if !ok {
-		return
+		return nil
 	}
 
 	funcDef := &funcDef{}
 	funcDef.ServiceName = td.ServiceName
+	funcDef.MockType = td.MockType
+	funcDef.TypeArguments = td.TypeArguments
 	funcDef.Name = field.Names[0].Name
 
 	paramNames := make([]ParamName, 0, len(ftype.Params.List))
@@ -73,10 +206,19 @@ func (td *data) AppendFuncDef(field *ast.Field) {
 
 		} else {
 			for _, n := range p.Names {
-				paramNames = append(paramNames, paramName(p.Type, n.Name))
+				name := n.Name
+				if name == "_" || td.isLocal(name) {
+					// blank parameters can't be used as arguments nor as fields,
+					// and the locals of the generated method would shadow the others
+					name = "p" + strconv.Itoa(len(paramNames))
+				}
+				paramNames = append(paramNames, paramName(p.Type, name))
 				paramTypes = append(paramTypes, td.expressionType(p.Type))
 			}
 		}
+		if funcDef.Context == "" && isContext(p.Type) {
+			funcDef.Context = paramNames[len(paramNames)-1].string
+		}
 	}
 
 	if !td.UnnamedSig {
@@ -85,11 +227,21 @@ func (td *data) AppendFuncDef(field *ast.Field) {
 		funcDef.Signature = strings.Join(paramTypes, ", ")
 	}
 
+	for i, n := range paramNames {
+		funcDef.Params = append(funcDef.Params, paramDef{
+			Name:  n.string,
+			Field: exportedName(n.string),
+			Type:  varargType(paramTypes[i], n.IsVararg),
+		})
+	}
+
+	funcDef.Types = strings.Join(paramTypes, ", ")
 	funcDef.Args = strings.Join(expandNames(paramNames), ", ")
+	funcDef.ArgNames = strings.Join(justNames(paramNames), ", ")
+	funcDef.ArgsFormat = formatVerbs(paramTypes)
 
 	if ftype.Results == nil {
-		td.FuncDefs = append(td.FuncDefs, funcDef)
-		return
+		return funcDef
 	}
 
 	returnTypes := make([]string, 0, len(ftype.Results.List))
@@ -98,12 +250,50 @@ func (td *data) AppendFuncDef(field *ast.Field) {
 	for _, r := range ftype.Results.List {
 		returnTypes = append(returnTypes, td.expressionType(r.Type))
 		returnValues = append(returnValues, td.returnValue(r.Type))
+
+		names := []string{""}
+		if len(r.Names) > 0 {
+			names = names[:0]
+			for _, n := range r.Names {
+				names = append(names, n.Name)
+			}
+		}
+		for _, n := range names {
+			if n == "" || n == "_" {
+				n = "r" + strconv.Itoa(len(funcDef.Results))
+			}
+			funcDef.Results = append(funcDef.Results, paramDef{
+				Name:  n,
+				Field: exportedName(n),
+				Type:  td.expressionType(r.Type),
+			})
+		}
+	}
+
+	if last := ftype.Results.List[len(ftype.Results.List)-1]; isError(last.Type) {
+		funcDef.ErrorValues = strings.Join(append(returnValues[:len(returnValues)-1:len(returnValues)-1], "err"), ", ")
+	}
+
+	resultNames := resultNames(len(funcDef.Results), funcDef.Params)
+	resultTypes := make([]string, len(funcDef.Results))
+	for i, r := range funcDef.Results {
+		resultTypes[i] = r.Type
 	}
 
 	funcDef.Return = formatReturnTypes(returnTypes)
 	funcDef.ReturnValues = strings.Join(returnValues, ", ")
-
-	td.FuncDefs = append(td.FuncDefs, funcDef)
+	funcDef.DefaultValues = funcDef.ReturnValues
+	if funcDef.ErrorValues != "" && td.DefaultError != "" {
+		err := td.DefaultError
+		if err == NotImplemented {
+			err = `errors.New("` + td.MockType + "." + funcDef.Name + `: not implemented")`
+		}
+		funcDef.DefaultValues = strings.Join(append(returnValues[:len(returnValues)-1:len(returnValues)-1], err), ", ")
+	}
+	funcDef.NamedReturn = "(" + strings.Join(fn.Zips(resultNames, resultTypes, " "), ", ") + ")"
+	funcDef.ResultNames = strings.Join(resultNames, ", ")
+	funcDef.ResultsFormat = formatVerbs(resultTypes)
+	return funcDef
 }
 
 func (td *data) expressionType(expr ast.Expr) string {
@@ -145,6 +335,16 @@ func (td *data) expressionType(expr ast.Expr) string {
 	case *ast.Ellipsis:
 		return "..." + td.expressionType(t.Elt)
 
+	case *ast.IndexExpr:
+		return td.expressionType(t.X) + "[" + td.expressionType(t.Index) + "]"
+
+	case *ast.IndexListExpr:
+		indices := make([]string, len(t.Indices))
+		for i, idx := range t.Indices {
+			indices[i] = td.expressionType(idx)
+		}
+		return td.expressionType(t.X) + "[" + strings.Join(indices, ", ") + "]"
+
 	case *ast.UnaryExpr:
 		switch t.Op {
 		case token.TILDE:
@@ -262,12 +462,32 @@ func (td *data) returnValue(expr ast.Expr) string {
 		}
 		return tname + "{}"
 
+	case *ast.ChanType:
+		if td.SafeDefaults {
+			// a closed channel: receiving from it doesn't block
+			typ := td.expressionType(t)
+			return "func() " + typ + " { c := make(chan " + td.expressionType(t.Value) + "); close(c); return c }()"
+		}
+		return "nil"
+
+	case *ast.FuncType:
+		if td.SafeDefaults {
+			return td.noopFunc(t)
+		}
+		return "nil"
+
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		if td.SafeDefaults {
+			if seq := td.emptySeq(t); seq != "" {
+				return seq
+			}
+		}
+		return "*new(" + td.expressionType(t) + ")"
+
 	case
 		*ast.StarExpr,
-		*ast.FuncType,
 		*ast.MapType,
-		*ast.InterfaceType,
-		*ast.ChanType:
+		*ast.InterfaceType:
 		return "nil"
 
 	case *ast.ArrayType:
@@ -284,6 +504,63 @@ func (td *data) returnValue(expr ast.Expr) string {
 	}
 }
 
+// Returns a function literal of the given type that does nothing and returns zero values.
+func (td *data) noopFunc(fn *ast.FuncType) string {
+	var params, types, values []string
+	for _, p := range fn.Params.List {
+		for range max(len(p.Names), 1) {
+			params = append(params, td.expressionType(p.Type))
+		}
+	}
+	if fn.Results != nil {
+		for _, r := range fn.Results.List {
+			for range max(len(r.Names), 1) {
+				types = append(types, td.expressionType(r.Type))
+				values = append(values, td.returnValue(r.Type))
+			}
+		}
+	}
+	s := "func(" + strings.Join(params, ", ") + ")"
+	if len(types) == 0 {
+		return s + " {}"
+	}
+	if len(types) > 1 {
+		s += " (" + strings.Join(types, ", ") + ")"
+	} else {
+		s += " " + types[0]
+	}
+	return s + " { return " + strings.Join(values, ", ") + " }"
+}
+
+// Returns an empty iterator literal if expr is an iter.Seq or iter.Seq2 type, and an empty string otherwise.
+func (td *data) emptySeq(expr ast.Expr) string {
+	var (
+		x       ast.Expr
+		indices []ast.Expr
+	)
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		x, indices = t.X, []ast.Expr{t.Index}
+	case *ast.IndexListExpr:
+		x, indices = t.X, t.Indices
+	}
+	sel, ok := x.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "iter" {
+		return ""
+	}
+	if (sel.Sel.Name == "Seq" && len(indices) == 1) || (sel.Sel.Name == "Seq2" && len(indices) == 2) {
+		types := make([]string, len(indices))
+		for i, idx := range indices {
+			types[i] = td.expressionType(idx)
+		}
+		return "func(yield func(" + strings.Join(types, ", ") + ") bool) {}"
+	}
+	return ""
+}
+
 func (td *data) qualifiedName(ident *ast.Ident) string {
 	if td.Package == "" {
 		return ident.Name
@@ -299,11 +576,53 @@ func (td *data) isTypeParam(t *ast.Ident) bool {
 	return ok
 }
 
+func isContext(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && sel.Sel.Name == "Context"
+}
+
+func isError(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
 func paramName(expr ast.Expr, name string) ParamName {
 	_, isVararg := expr.(*ast.Ellipsis)
 	return ParamName{name, isVararg}
 }
 
+// Returns the fmt verbs that format values of the given types, separated by commas.
+// Functions are formatted with %p, since vet reports %v on a func value as a missing call.
+func formatVerbs(types []string) string {
+	verbs := make([]string, len(types))
+	for i, t := range types {
+		verbs[i] = "%v"
+		if strings.HasPrefix(t, "func(") {
+			verbs[i] = "%p"
+		}
+	}
+	return strings.Join(verbs, ", ")
+}
+
+// Returns the names r0, r1, ... of n results, or rr0, rr1, ... if they clash with a parameter.
+func resultNames(n int, params []paramDef) []string {
+	prefix := "r"
+	for {
+		names := make([]string, n)
+		for i := range names {
+			names[i] = prefix + strconv.Itoa(i)
+		}
+		if !slices.ContainsFunc(params, func(p paramDef) bool { return slices.Contains(names, p.Name) }) {
+			return names
+		}
+		prefix += "r"
+	}
+}
+
 func justNames(paramNames []ParamName) []string {
 	ss := make([]string, 0, len(paramNames))
 	for _, n := range paramNames {
@@ -320,6 +639,20 @@ func expandNames(paramNames []ParamName) []string {
 	return ss
 }
 
+// Returns the type of a variadic parameter as a slice
+func varargType(t string, isVararg bool) string {
+	if isVararg {
+		return "[]" + strings.TrimPrefix(t, "...")
+	}
+	return t
+}
+
+func exportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
 func formatReturnTypes(r []string) string {
 	switch len(r) {
 	case 0:
@@ -197,6 +197,9 @@ type Foo[T any, R ~[]byte] interface {
 			"Get() <-chan amqp.Delivery":   "nil",
 			"Get() [2]string":              "[2]string{}",
 			"Get() [2]map[string]struct{}": "[2]map[string]struct{}{}",
+			"Get() test.Box[int]":          "*new(test.Box[int])",
+			"Get() iter.Seq2[int, T]":      "*new(iter.Seq2[int, T])",
+			"Get() func() error":           "nil",
 		}
 
 		for method, expected := range cases {
@@ -221,4 +224,39 @@ type Foo[T any, R ~[]byte] interface {
 			assert.Equal(t, expected, retval)
 		}
 	})
+	t.Run("write safe return values", func(t *testing.T) {
+		cases := map[string]string{
+			"Get() chan int":                  "func() chan int { c := make(chan int); close(c); return c }()",
+			"Get() <-chan amqp.Delivery":      "func() <-chan amqp.Delivery { c := make(chan amqp.Delivery); close(c); return c }()",
+			"Get() func()":                    "func() {}",
+			"Get() func(a, b int) error":      "func(int, int) error { return nil }",
+			"Get() func(...string) (T, bool)": "func(...string) (T, bool) { return *new(T), false }",
+			"Get() func() <-chan int":         "func() <-chan int { return func() <-chan int { c := make(chan int); close(c); return c }() }",
+			"Get() iter.Seq[T]":               "func(yield func(T) bool) {}",
+			"Get() iter.Seq2[int, T]":         "func(yield func(int, T) bool) {}",
+			"Get() test.Box[int]":             "*new(test.Box[int])",
+			"Get() error":                     "nil",
+		}
+
+		for method, expected := range cases {
+			src := fmt.Sprintf(templMethod, method)
+
+			f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.DeclarationErrors)
+			assert.Nil(t, err)
+
+			spec, err := gomock.GetInterfaceSpec(f, "")
+			assert.Nil(t, err)
+
+			m := spec.Type.(*ast.InterfaceType).Methods.List[0]
+			r := m.Type.(*ast.FuncType).Results.List[0]
+
+			td := &data{
+				SafeDefaults: true,
+				typeParamSet: map[string]struct{}{"T": {}},
+			}
+
+			retval := td.returnValue(r.Type)
+			assert.Equal(t, expected, retval, method)
+		}
+	})
 }
@@ -2,9 +2,13 @@ package template
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/format"
+	"go/token"
+	"path"
+	"slices"
 	"strings"
 	"text/template"
 	"unicode"
@@ -12,6 +16,15 @@ import (
 	"github.com/vibridi/gomock/v3/parser"
 )
 
+// expectPackage is the import path of the runtime package of the expectations API
+const expectPackage = "github.com/vibridi/gomock/v3/expect"
+
+// faultPackage is the import path of the runtime package of the fault injector
+const faultPackage = "github.com/vibridi/gomock/v3/fault"
+
+// NotImplemented is the value of Opts.DefaultError that makes each default implementation return its own error
+const NotImplemented = "not-implemented"
+
 type Opts struct {
 	Qualify          bool
 	Export           bool
@@ -22,6 +35,32 @@ type Opts struct {
 	Underlying       []string
 	ImportAliases    []string
 	PrefixPackage    bool
+	Methods          []string // if not empty, only these methods are mocked
+	ExcludeMethods   []string // methods that are not mocked
+	Strict           bool     // fail the test on calls to methods without override
+	Delegate         bool     // generate an option that calls through to a real implementation
+	CannedReturns    bool     // generate withReturn and withError helpers that return fixed values
+	SequencedReturns bool     // generate withReturns helpers that return a sequence of values
+	Record           bool     // record the calls to each method with their arguments
+	Expect           bool     // generate an expectations API backed by the expect package
+	Sequence         bool     // generate an option that attaches the mock to an expect.Sequence
+	Gates            bool     // generate gates that block calls until the test releases them
+	ContextAware     bool     // default implementations return the error of a done context
+	Faults           bool     // generate an option that attaches a fault.Injector to the mock
+	VerifyOverrides  bool     // generate a check that fails the test for overrides that were never called
+	Trace            bool     // generate options that log each call through testing.TB or slog
+	Reset            bool     // generate Reset and Apply methods that reconfigure the mock
+	SafeDefaults     bool     // default implementations return closed channels, empty iterators and no-op functions
+	DefaultError     string   // error returned by default implementations: NotImplemented or a sentinel, e.g. io.EOF
+	SourceImport     string   // import path of the source package, imported by qualified mocks that are written to another package
+}
+
+// Reports whether the method name gets mock helpers. The other methods panic when called.
+func (opts Opts) isMocked(name string) bool {
+	if len(opts.Methods) > 0 && !slices.Contains(opts.Methods, name) {
+		return false
+	}
+	return !slices.Contains(opts.ExcludeMethods, name)
 }
 
 // Executes the template according to the write options
@@ -42,6 +81,7 @@ func Exec(mock *parser.MockData, opts Opts) ([]byte, error) {
 
 	var buf bytes.Buffer
 	t := template.Must(template.New("mock").Parse(mockTemplate))
+	template.Must(t.Parse(Recorder))
 	if err := t.Execute(&buf, d); err != nil {
 		return nil, err
 	}
@@ -51,21 +91,103 @@ func Exec(mock *parser.MockData, opts Opts) ([]byte, error) {
 
 func buildData(mock *parser.MockData, opts Opts) (*data, error) {
 	d := &data{
-		Qualify:       opts.Qualify,
-		Export:        opts.Export,
-		Disambiguate:  opts.Disambiguate,
-		Package:       mock.PackageName,
-		ServiceName:   mock.InterfaceName,
-		InterfaceName: mock.InterfaceName,
-		UnnamedSig:    opts.UnnamedSignature,
-		Underlying:    make(map[string]string, len(opts.Underlying)),
-		Aliases:       make(map[string]string, len(opts.ImportAliases)),
-		PrefixPackage: opts.PrefixPackage,
+		Qualify:          opts.Qualify,
+		Export:           opts.Export,
+		Disambiguate:     opts.Disambiguate,
+		Package:          mock.PackageName,
+		ServiceName:      mock.InterfaceName,
+		InterfaceName:    mock.InterfaceName,
+		UnnamedSig:       opts.UnnamedSignature,
+		Underlying:       make(map[string]string, len(opts.Underlying)),
+		Aliases:          make(map[string]string, len(opts.ImportAliases)),
+		importNames:      make(map[string]string),
+		PrefixPackage:    opts.PrefixPackage,
+		Strict:           opts.Strict,
+		Delegate:         opts.Delegate,
+		CannedReturns:    opts.CannedReturns,
+		SequencedReturns: opts.SequencedReturns,
+		Record:           opts.Record,
+		Expect:           opts.Expect,
+		Sequence:         opts.Sequence,
+		Gates:            opts.Gates,
+		ContextAware:     opts.ContextAware,
+		Faults:           opts.Faults,
+		VerifyOverrides:  opts.VerifyOverrides,
+		Trace:            opts.Trace,
+		Reset:            opts.Reset,
+		SafeDefaults:     opts.SafeDefaults,
+		Locked:           opts.Record || opts.Gates || opts.VerifyOverrides || opts.Reset,
+		ReturnsMock:      opts.Record || opts.Expect || opts.Gates || opts.VerifyOverrides || opts.Reset,
 		// computed
 		FuncDefs:      nil,
 		TypeArguments: "",
 		TypeParamList: "",
 	}
+	if opts.Strict {
+		if opts.StructStyle {
+			return nil, errors.New("strict mode is not supported in struct style")
+		}
+		d.addImport("testing")
+	}
+	if opts.Delegate && opts.StructStyle {
+		return nil, errors.New("delegation is not supported in struct style")
+	}
+	if opts.CannedReturns && opts.StructStyle {
+		return nil, errors.New("canned returns are not supported in struct style")
+	}
+	if opts.SequencedReturns && opts.StructStyle {
+		return nil, errors.New("sequenced returns are not supported in struct style")
+	}
+	if opts.Expect {
+		if opts.StructStyle {
+			return nil, errors.New("expectations are not supported in struct style")
+		}
+		d.addImport("testing")
+		d.addImport(expectPackage)
+	}
+	if opts.Sequence {
+		if opts.StructStyle {
+			return nil, errors.New("sequences are not supported in struct style")
+		}
+		d.addImport(expectPackage)
+	}
+	if opts.Gates && opts.StructStyle {
+		return nil, errors.New("gates are not supported in struct style")
+	}
+	if opts.Faults {
+		if opts.StructStyle {
+			return nil, errors.New("fault injection is not supported in struct style")
+		}
+		d.addImport(faultPackage)
+	}
+	if opts.VerifyOverrides {
+		if opts.StructStyle {
+			return nil, errors.New("override verification is not supported in struct style")
+		}
+		d.addImport("testing")
+	}
+	if opts.Reset && opts.StructStyle {
+		return nil, errors.New("reset is not supported in struct style")
+	}
+	if opts.Trace {
+		if opts.StructStyle {
+			return nil, errors.New("tracing is not supported in struct style")
+		}
+		d.addImport("fmt")
+		d.addImport("log/slog")
+		d.addImport("testing")
+		d.addImport("time")
+	}
+	// the mutex of the mock state, and the one of the withReturns helpers
+	if d.Locked || opts.SequencedReturns {
+		d.addImport("sync")
+	}
+	if opts.Record {
+		d.addImport("context")
+		d.addImport("fmt")
+		d.addImport("slices")
+	}
+
 	// Override the service name with the one supplied by the user, if any
 	if opts.MockName != "" {
 		d.ServiceName = opts.MockName
@@ -93,24 +215,100 @@ func buildData(mock *parser.MockData, opts Opts) (*data, error) {
 		d.Aliases[p] = a
 	}
 
+	if opts.SourceImport != "" && opts.Qualify {
+		d.addImport(opts.SourceImport)
+		if path.Base(opts.SourceImport) != d.Package {
+			d.importNames[opts.SourceImport] = d.Package
+		}
+	}
+
+	var errorImport string
+	if opts.DefaultError != "" {
+		expr, path, err := defaultError(opts.DefaultError)
+		if err != nil {
+			return nil, err
+		}
+		d.DefaultError = expr
+		errorImport = path
+	}
+
+	d.MockType = "mock" + d.ServiceName
+	if opts.StructStyle && opts.Export {
+		d.MockType = "Mock" + d.ServiceName
+	}
+
 	d.AddTypeParameters(mock.TypeParamFields)
 
-	for _, field := range mock.MethodFields {
+	appendMethod := func(field *ast.Field) {
+		if len(field.Names) > 0 && !opts.isMocked(field.Names[0].Name) {
+			d.AppendStub(field)
+			return
+		}
 		d.AppendFuncDef(field)
 	}
 
+	for _, field := range mock.MethodFields {
+		appendMethod(field)
+	}
+
 	for _, field := range mock.Components {
 		local := mock.InheritedMethodFields[field.Type.(*ast.Ident).Name]
 		for _, lm := range local {
-			d.AppendFuncDef(lm)
+			appendMethod(lm)
 		}
 	}
 
 	for _, field := range mock.ExternalComponents {
 		imported := mock.InheritedMethodFields[field.Type.(*ast.SelectorExpr).Sel.Name]
 		for _, im := range imported {
-			d.AppendFuncDef(im)
+			appendMethod(im)
+		}
+	}
+
+	for _, field := range mock.IgnoredMethodFields {
+		d.AppendStub(field)
+	}
+
+	for _, name := range slices.Concat(opts.Methods, opts.ExcludeMethods) {
+		if !d.hasMethod(name) {
+			return nil, fmt.Errorf("unknown method: %s", name)
+		}
+	}
+	// the default error is returned only by the default implementations of methods that return an error,
+	// which strict options-style mocks don't have
+	if errorImport != "" && (opts.StructStyle || !opts.Strict) && slices.ContainsFunc(d.FuncDefs, func(fd *funcDef) bool {
+		return fd.ErrorValues != ""
+	}) {
+		d.addImport(errorImport)
+	}
+	if opts.Reset {
+		for _, name := range []string{"Reset", "Apply"} {
+			if d.hasMethod(name) {
+				return nil, fmt.Errorf("cannot generate %s: the interface has a method with the same name", name)
+			}
 		}
 	}
 	return d, nil
 }
+
+// Parses the value of the default error option into the expression returned by default implementations,
+// and the path of the package it imports, if any. Sentinels are either identifiers of the mock's package,
+// e.g. ErrNotImplemented, or qualified with an import path, e.g. io.EOF or github.com/foo/errs.ErrNotImplemented.
+func defaultError(value string) (expr, path string, err error) {
+	if value == NotImplemented {
+		return value, "errors", nil
+	}
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		if !token.IsIdentifier(value) {
+			return "", "", fmt.Errorf("invalid default error: %s", value)
+		}
+		return value, "", nil
+	}
+	path, name := value[:i], value[i+1:]
+	pkg := path[strings.LastIndex(path, "/")+1:]
+	if !token.IsIdentifier(pkg) || !token.IsIdentifier(name) {
+		return "", "", fmt.Errorf("invalid default error: %s", value)
+	}
+	return pkg + "." + name, path, nil
+}
@@ -3,6 +3,7 @@ package template
 import (
 	"go/ast"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -653,6 +654,1188 @@ func NewMockFooInterface(opt ...mockFooInterfaceOption) foo.Interface {
 		}
 	})
 
+	t.Run("ignored methods", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get() string
+	//gomock:ignore
+	Set(v string) error
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), "funcSet")
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Set(v string) error {
+	panic("mockTestInterface.Set: not mocked")
+}
+`)
+
+		out, err = Exec(md, Opts{StructStyle: true, Export: true})
+		require.Nil(t, err)
+		assert.Equal(t, `
+type MockTestInterface struct {
+	GetFunc func() string
+}
+
+func (m *MockTestInterface) Get() string {
+	if m.GetFunc != nil {
+		return m.GetFunc()
+	}
+	return ""
+}
+
+func (m *MockTestInterface) Set(v string) error {
+	panic("MockTestInterface.Set: not mocked")
+}
+`, string(out))
+	})
+
+	t.Run("selected methods", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) string
+	Put(key, v string) error
+	Delete(key string) error
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Methods: []string{"Get", "Put"}, ExcludeMethods: []string{"Put"}})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func withFuncGet(")
+		assert.NotContains(t, string(out), "withFuncPut")
+		assert.NotContains(t, string(out), "withFuncDelete")
+		assert.Contains(t, string(out), `panic("mockTestInterface.Put: not mocked")`)
+		assert.Contains(t, string(out), `panic("mockTestInterface.Delete: not mocked")`)
+
+		_, err = Exec(md, Opts{Methods: []string{"Gett"}})
+		assert.EqualError(t, err, "unknown method: Gett")
+	})
+
+	t.Run("sequenced returns", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) (v string, err error)
+	Next() (int, bool)
+	Close()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), "Exhausted")
+		assert.NotContains(t, string(out), "GetResult")
+
+		_, err = Exec(md, Opts{SequencedReturns: true, StructStyle: true})
+		assert.EqualError(t, err, "sequenced returns are not supported in struct style")
+
+		out, err = Exec(md, Opts{SequencedReturns: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "\t\"sync\"\n")
+		assert.Contains(t, string(out), `
+type mockTestInterfaceGetResult struct {
+	V   string
+	Err error
+}
+`)
+		assert.Contains(t, string(out), `
+type mockTestInterfaceNextResult struct {
+	R0 int
+	R1 bool
+}
+`)
+		assert.Contains(t, string(out), "func withReturnsGet(results []mockTestInterfaceGetResult, exhausted mockTestInterfaceExhausted) mockTestInterfaceOption {")
+		assert.Contains(t, string(out), "o.funcGet = func(string) (string, error) {")
+		assert.Contains(t, string(out), "return r.V, r.Err")
+		assert.NotContains(t, string(out), "withReturnsClose")
+
+		out, err = Exec(md, Opts{SequencedReturns: true, Export: true, Disambiguate: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func WithReturnsTestInterfaceNext(")
+	})
+
+	t.Run("canned returns", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) (o string, _ int, err error)
+	Next() (int, bool)
+	Close()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), "withReturn")
+		assert.NotContains(t, string(out), "withError")
+
+		_, err = Exec(md, Opts{CannedReturns: true, StructStyle: true})
+		assert.EqualError(t, err, "canned returns are not supported in struct style")
+
+		// the parameters don't take the names of the results, which may shadow the option argument
+		out, err = Exec(md, Opts{CannedReturns: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func withReturnGet(r0 string, r1 int, r2 error) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = func(string) (string, int, error) {
+			return r0, r1, r2
+		}
+	}
+}
+
+func withErrorGet(err error) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = func(string) (string, int, error) {
+			return "", 0, err
+		}
+	}
+}
+`)
+		assert.Contains(t, string(out), "func withReturnNext(r0 int, r1 bool) mockTestInterfaceOption {")
+		assert.NotContains(t, string(out), "withErrorNext")
+		assert.NotContains(t, string(out), "withReturnClose")
+
+		out, err = Exec(md, Opts{CannedReturns: true, Export: true, Disambiguate: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func WithReturnTestInterfaceGet(")
+		assert.Contains(t, string(out), "func WithErrorTestInterfaceGet(")
+	})
+
+	t.Run("expectations", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string, opts ...int) (string, error)
+	Close()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		_, err = Exec(md, Opts{Expect: true, StructStyle: true})
+		assert.EqualError(t, err, "expectations are not supported in struct style")
+
+		out, err := Exec(md, Opts{Expect: true})
+		require.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(out), `
+import (
+	"testing"
+
+	"github.com/vibridi/gomock/v3/expect"
+)
+`))
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Get(key string, opts ...int) (string, error) {
+	if c := m.expect.Call("Get", key, opts); c != nil {
+		r, _ := c.Result().(mockTestInterfaceGetResult)
+		return r.R0, r.R1
+	}
+	return m.options.funcGet(key, opts...)
+}
+`)
+		assert.Contains(t, string(out), `
+	if c := m.expect.Call("Close"); c != nil {
+		return
+	}
+`)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) ExpectGet(key any, opts any) *mockTestInterfaceGetExpectation {
+	return &mockTestInterfaceGetExpectation{m.expect.Expect("Get", key, opts)}
+}
+
+func (e *mockTestInterfaceGetExpectation) Return(r0 string, r1 error) *mockTestInterfaceGetExpectation {
+	e.call.SetResult(mockTestInterfaceGetResult{r0, r1})
+	return e
+}
+`)
+		assert.NotContains(t, string(out), "func (e *mockTestInterfaceCloseExpectation) Return(")
+		assert.Contains(t, string(out), `
+func newMockTestInterface(t testing.TB, opt ...mockTestInterfaceOption) *mockTestInterface {
+	opts := defaultMockTestInterfaceOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	return &mockTestInterface{
+		options: opts,
+		expect:  expect.NewController(t),
+	}
+}`)
+	})
+
+	t.Run("sequence", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) string
+	Close()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		_, err = Exec(md, Opts{Sequence: true, StructStyle: true})
+		assert.EqualError(t, err, "sequences are not supported in struct style")
+
+		out, err := Exec(md, Opts{Sequence: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "\n\t\"github.com/vibridi/gomock/v3/expect\"\n")
+		assert.Contains(t, string(out), "\tsequence *expect.Sequence\n")
+		assert.Contains(t, string(out), `
+func withRecorder(seq *expect.Sequence) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.sequence = seq
+	}
+}
+`)
+		assert.Contains(t, string(out), `
+	m.options.sequence.Add("TestInterface.Get", key)
+`)
+		assert.Contains(t, string(out), `
+	m.options.sequence.Add("TestInterface.Close")
+`)
+
+		out, err = Exec(md, Opts{Sequence: true, Export: true, Disambiguate: true, MockName: "Foo"})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func WithRecorderFoo(seq *expect.Sequence) mockFooOption {")
+		assert.Contains(t, string(out), `m.options.sequence.Add("Foo.Get", key)`)
+	})
+
+	t.Run("gates", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(ctx context.Context, key string) (string, error)
+	Len(context.Context) int
+	Close()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		_, err = Exec(md, Opts{Gates: true, StructStyle: true})
+		assert.EqualError(t, err, "gates are not supported in struct style")
+
+		out, err := Exec(md, Opts{Gates: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func newMockTestInterface(opt ...mockTestInterfaceOption) *mockTestInterface {")
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Get(ctx context.Context, key string) (string, error) {
+	m.mu.RLock()
+	f := m.options.funcGet
+	g := m.gateGet
+	m.mu.RUnlock()
+	if g != nil {
+		select {
+		case <-g.released:
+		case <-ctx.Done():
+			err := ctx.Err()
+			return "", err
+		}
+		if r := g.result; r != nil {
+			return r.R0, r.R1
+		}
+	}
+	return f(ctx, key)
+}
+`)
+		assert.Contains(t, string(out), `
+		case <-p0.Done():
+			return 0
+`)
+		assert.Contains(t, string(out), `
+	if g != nil {
+		<-g.released
+	}
+	f()
+`)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) GateGet() *mockTestInterfaceGetGate {
+	g := &mockTestInterfaceGetGate{released: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gateGet = g
+	return g
+}
+`)
+		assert.Contains(t, string(out), "func (g *mockTestInterfaceGetGate) ReleaseWith(r0 string, r1 error) {")
+		assert.Contains(t, string(out), "func (g *mockTestInterfaceCloseGate) Release() {")
+		assert.NotContains(t, string(out), "func (g *mockTestInterfaceCloseGate) ReleaseWith(")
+
+		// without an error to return, a call cancelled at the gate returns the default values
+		const safe = `
+package test
+type TestInterface interface {
+	Watch(ctx context.Context) <-chan string
+}
+`
+		md, err = gomock.Parse("", safe, "")
+		require.Nil(t, err)
+
+		out, err = Exec(md, Opts{Gates: true, SafeDefaults: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+		case <-ctx.Done():
+			return func() <-chan string { c := make(chan string); close(c); return c }()
+`)
+	})
+
+	t.Run("context aware", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface[T any] interface {
+	Get(ctx context.Context, key string) (T, error)
+	Len(ctx context.Context) int
+	Do(string) error
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{ContextAware: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+		funcGet: func(ctx context.Context, key string) (T, error) {
+			if err := ctx.Err(); err != nil {
+				return *new(T), err
+			}
+			return *new(T), nil
+		},
+		funcLen: func(ctx context.Context) int {
+			return 0
+		},
+		funcDo: func(p0 string) error {
+			return nil
+		},
+`)
+
+		out, err = Exec(md, Opts{})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), "ctx.Err()")
+	})
+
+	t.Run("faults", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(ctx context.Context, key string) (string, error)
+	Len() int
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Faults: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+import (
+	"github.com/vibridi/gomock/v3/fault"
+)
+`)
+		assert.Contains(t, string(out), `
+func withInjector(inj *fault.Injector) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.injector = inj
+	}
+}
+`)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Get(ctx context.Context, key string) (string, error) {
+	if err := m.options.injector.InjectContext(ctx, "Get", true); err != nil {
+		return "", err
+	}
+	return m.options.funcGet(ctx, key)
+}
+
+func (m *mockTestInterface) Len() int {
+	m.options.injector.Inject("Len", false)
+	return m.options.funcLen()
+}
+`)
+
+		_, err = Exec(md, Opts{Faults: true, StructStyle: true})
+		assert.EqualError(t, err, "fault injection is not supported in struct style")
+	})
+
+	t.Run("delegate", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) (string, error)
+	Close()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), "withDelegate")
+
+		_, err = Exec(md, Opts{Delegate: true, StructStyle: true})
+		assert.EqualError(t, err, "delegation is not supported in struct style")
+
+		out, err = Exec(md, Opts{Delegate: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func withDelegate(impl TestInterface) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = impl.Get
+		o.funcClose = impl.Close
+	}
+}
+`)
+
+		out, err = Exec(md, Opts{Delegate: true, Export: true, Disambiguate: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func WithDelegateTestInterface(impl TestInterface) mockTestInterfaceOption {")
+	})
+
+	t.Run("verify overrides", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) (string, error)
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{VerifyOverrides: true, Delegate: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func withFuncGet(f func(key string) (string, error)) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = f
+		o.overrideGet = true
+	}
+}
+
+func withDelegate(impl TestInterface) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = impl.Get
+		o.overrideGet = false
+	}
+}
+`)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Get(key string) (string, error) {
+	m.mu.Lock()
+	m.countGet++
+	f := m.options.funcGet
+	m.mu.Unlock()
+	return f(key)
+}
+
+func (m *mockTestInterface) AssertOverridesCalled(t testing.TB) bool {
+	t.Helper()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ok := true
+	if m.options.overrideGet && m.countGet == 0 {
+		t.Errorf("mockTestInterface.Get is overridden but was never called")
+		ok = false
+	}
+	return ok
+}
+`)
+		assert.Contains(t, string(out), `
+func newMockTestInterface(opt ...mockTestInterfaceOption) *mockTestInterface {
+	opts := defaultMockTestInterfaceOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	return &mockTestInterface{
+		options: opts,
+	}
+}`)
+
+		out, err = Exec(md, Opts{VerifyOverrides: true, Strict: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+	m := &mockTestInterface{
+		t:       t,
+		options: opts,
+	}
+	t.Cleanup(func() {
+		m.AssertOverridesCalled(t)
+	})
+	return m
+}`)
+
+		_, err = Exec(md, Opts{VerifyOverrides: true, StructStyle: true})
+		assert.EqualError(t, err, "override verification is not supported in struct style")
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) (v string, err error)
+	Reset()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Trace: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+)
+`)
+		assert.Contains(t, string(out), `
+func withTrace(t testing.TB) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.trace = func(method, args, results string, d time.Duration) {
+			t.Logf("mockTestInterface.%s(%s) -> (%s) in %s", method, args, results, d)
+		}
+	}
+}
+
+func withTraceLogger(l *slog.Logger) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.trace = func(method, args, results string, d time.Duration) {
+			l.Info("mockTestInterface."+method, "args", args, "results", results, "duration", d)
+		}
+	}
+}
+`)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Get(key string) (r0 string, r1 error) {
+	if m.options.trace != nil {
+		defer func(start time.Time) {
+			m.options.trace("Get", fmt.Sprintf("%v", key), fmt.Sprintf("%v, %v", r0, r1), time.Since(start))
+		}(time.Now())
+	}
+	return m.options.funcGet(key)
+}
+
+func (m *mockTestInterface) Reset() {
+	if m.options.trace != nil {
+		defer func(start time.Time) {
+			m.options.trace("Reset", "", "", time.Since(start))
+		}(time.Now())
+	}
+	m.options.funcReset()
+}
+`)
+
+		_, err = Exec(md, Opts{Trace: true, StructStyle: true})
+		assert.EqualError(t, err, "tracing is not supported in struct style")
+
+		// the results are renamed when they clash with a parameter
+		md, err = gomock.Parse("", `
+package test
+type TestInterface interface {
+	Get(r0 string, r1 int) (string, error)
+}
+`, "")
+		require.Nil(t, err)
+		out, err = Exec(md, Opts{Trace: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Get(r0 string, r1 int) (rr0 string, rr1 error) {
+	if m.options.trace != nil {
+		defer func(start time.Time) {
+			m.options.trace("Get", fmt.Sprintf("%v, %v", r0, r1), fmt.Sprintf("%v, %v", rr0, rr1), time.Since(start))
+		}(time.Now())
+	}
+	return m.options.funcGet(r0, r1)
+}
+`)
+
+		// functions are formatted with %p, which vet accepts
+		md, err = gomock.Parse("", `
+package test
+type TestInterface interface {
+	Walk(f func(string) error, fs ...func()) (func(), error)
+}
+`, "")
+		require.Nil(t, err)
+		out, err = Exec(md, Opts{Trace: true, Strict: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `m.options.trace("Walk", fmt.Sprintf("%p, %v", f, fs), fmt.Sprintf("%p, %v", r0, r1), time.Since(start))`)
+		assert.Contains(t, string(out), `m.t.Fatalf("unexpected call to mockTestInterface.Walk(%p, %v)", f, fs)`)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) (string, error)
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Reset: true, Record: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.options = defaultMockTestInterfaceOptions
+	m.callsGet = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+func (m *mockTestInterface) Apply(opt ...mockTestInterfaceOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, o := range opt {
+		o(&m.options)
+	}
+}
+`)
+		assert.Contains(t, string(out), "func newMockTestInterface(opt ...mockTestInterfaceOption) *mockTestInterface {")
+
+		out, err = Exec(md, Opts{Reset: true, Strict: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.options = mockTestInterfaceOptions{}
+}
+`)
+
+		// the expectations are cleared, the attachments are kept
+		out, err = Exec(md, Opts{Reset: true, Expect: true, Sequence: true, Faults: true, Trace: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	opts := defaultMockTestInterfaceOptions
+	opts.sequence = m.options.sequence
+	opts.injector = m.options.injector
+	opts.trace = m.options.trace
+	m.options = opts
+	m.expect.Reset()
+}
+`)
+
+		_, err = Exec(md, Opts{Reset: true, StructStyle: true})
+		assert.EqualError(t, err, "reset is not supported in struct style")
+
+		md, err = gomock.Parse("", `
+package test
+type TestInterface interface {
+	Reset()
+}
+`, "")
+		require.Nil(t, err)
+		_, err = Exec(md, Opts{Reset: true})
+		assert.EqualError(t, err, "cannot generate Reset: the interface has a method with the same name")
+	})
+
+	t.Run("default error", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string) (string, error)
+	Len() int
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{DefaultError: NotImplemented, CannedReturns: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+import (
+	"errors"
+)
+`)
+		assert.Contains(t, string(out), `
+var defaultMockTestInterfaceOptions = mockTestInterfaceOptions{
+	funcGet: func(key string) (string, error) {
+		return "", errors.New("mockTestInterface.Get: not implemented")
+	},
+	funcLen: func() int {
+		return 0
+	},
+}
+`)
+		// overrides still return zero values
+		assert.Contains(t, string(out), `
+func withErrorGet(err error) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = func(string) (string, error) {
+			return "", err
+		}
+	}
+}
+`)
+
+		out, err = Exec(md, Opts{DefaultError: "github.com/foo/errs.ErrNotImplemented"})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+import (
+	"github.com/foo/errs"
+)
+`)
+		assert.Contains(t, string(out), `return "", errs.ErrNotImplemented`)
+
+		out, err = Exec(md, Opts{DefaultError: "ErrNotImplemented", StructStyle: true, Export: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func (m *MockTestInterface) Get(key string) (string, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(key)
+	}
+	return "", ErrNotImplemented
+}
+`)
+
+		out, err = Exec(md, Opts{DefaultError: NotImplemented, StructStyle: true, Export: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `return "", errors.New("MockTestInterface.Get: not implemented")`)
+		assert.Contains(t, string(out), `"errors"`)
+
+		// strict mocks have no default implementations, so the error isn't used
+		out, err = Exec(md, Opts{DefaultError: "io.EOF", Strict: true})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), `"io"`)
+		assert.NotContains(t, string(out), "io.EOF")
+
+		out, err = Exec(md, Opts{DefaultError: NotImplemented, Strict: true})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), `"errors"`)
+		assert.NotContains(t, string(out), "not implemented")
+
+		// nor when no method returns an error, in either style
+		md, err = gomock.Parse("", `
+package test
+type TestInterface interface {
+	Len() int
+}
+`, "")
+		require.Nil(t, err)
+		for _, structStyle := range []bool{false, true} {
+			out, err = Exec(md, Opts{DefaultError: "io.EOF", StructStyle: structStyle})
+			require.Nil(t, err)
+			assert.NotContains(t, string(out), `"io"`)
+		}
+
+		for _, v := range []string{"not implemented", "errs.", "foo-bar.Err"} {
+			_, err = Exec(md, Opts{DefaultError: v})
+			assert.EqualError(t, err, "invalid default error: "+v)
+		}
+	})
+
+	t.Run("source import", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get() Item
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Qualify: true, SourceImport: "example.com/test"})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+import (
+	"example.com/test"
+)
+`)
+		assert.Contains(t, string(out), "func (m *mockTestInterface) Get() test.Item {")
+
+		out, err = Exec(md, Opts{Qualify: true, SourceImport: "example.com/test/v2"})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+	test "example.com/test/v2"
+`)
+
+		out, err = Exec(md, Opts{SourceImport: "example.com/test"})
+		require.Nil(t, err)
+		assert.NotContains(t, string(out), "example.com/test")
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string, opts ...int) string
+	Reset()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Strict: true})
+		require.Nil(t, err)
+		assert.Equal(t, `
+import (
+	"testing"
+)
+
+type mockTestInterface struct {
+	t       testing.TB
+	options mockTestInterfaceOptions
+}
+
+type mockTestInterfaceOptions struct {
+	funcGet   func(key string, opts ...int) string
+	funcReset func()
+}
+
+type mockTestInterfaceOption func(*mockTestInterfaceOptions)
+
+func withFuncGet(f func(key string, opts ...int) string) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = f
+	}
+}
+
+func withFuncReset(f func()) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcReset = f
+	}
+}
+
+func (m *mockTestInterface) Get(key string, opts ...int) string {
+	if m.options.funcGet == nil {
+		m.t.Helper()
+		m.t.Fatalf("unexpected call to mockTestInterface.Get(%v, %v)", key, opts)
+	}
+	return m.options.funcGet(key, opts...)
+}
+
+func (m *mockTestInterface) Reset() {
+	if m.options.funcReset == nil {
+		m.t.Helper()
+		m.t.Fatalf("unexpected call to mockTestInterface.Reset()")
+	}
+	m.options.funcReset()
+}
+
+func newMockTestInterface(t testing.TB, opt ...mockTestInterfaceOption) TestInterface {
+	opts := mockTestInterfaceOptions{}
+	for _, o := range opt {
+		o(&opts)
+	}
+	return &mockTestInterface{
+		t:       t,
+		options: opts,
+	}
+}`, string(out))
+
+		_, err = Exec(md, Opts{Strict: true, StructStyle: true})
+		assert.EqualError(t, err, "strict mode is not supported in struct style")
+	})
+
+	t.Run("record calls", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Get(key string, opts ...int) string
+	Reset()
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Record: true})
+		require.Nil(t, err)
+		assert.Equal(t, `
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type mockTestInterface struct {
+	mu          sync.RWMutex
+	options     mockTestInterfaceOptions
+	notify      chan struct{}
+	callsGet    []mockTestInterfaceGetCall
+	calledGet   []chan mockTestInterfaceGetCall
+	callsReset  []mockTestInterfaceResetCall
+	calledReset []chan mockTestInterfaceResetCall
+}
+
+type mockTestInterfaceOptions struct {
+	funcGet   func(key string, opts ...int) string
+	funcReset func()
+}
+
+var defaultMockTestInterfaceOptions = mockTestInterfaceOptions{
+	funcGet: func(key string, opts ...int) string {
+		return ""
+	},
+	funcReset: func() {
+		return
+	},
+}
+
+type mockTestInterfaceOption func(*mockTestInterfaceOptions)
+
+func withFuncGet(f func(key string, opts ...int) string) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcGet = f
+	}
+}
+
+func withFuncReset(f func()) mockTestInterfaceOption {
+	return func(o *mockTestInterfaceOptions) {
+		o.funcReset = f
+	}
+}
+
+func (m *mockTestInterface) Get(key string, opts ...int) string {
+	m.mu.Lock()
+	m.recordGet(mockTestInterfaceGetCall{key, opts})
+	f := m.options.funcGet
+	m.mu.Unlock()
+	return f(key, opts...)
+}
+
+func (m *mockTestInterface) Reset() {
+	m.mu.Lock()
+	m.recordReset(mockTestInterfaceResetCall{})
+	f := m.options.funcReset
+	m.mu.Unlock()
+	f()
+}
+
+type mockTestInterfaceGetCall struct {
+	Key  string
+	Opts []int
+}
+
+func (m *mockTestInterface) GetCalls() []mockTestInterfaceGetCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsGet)
+}
+
+func (m *mockTestInterface) GetCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsGet)
+}
+
+func (m *mockTestInterface) WaitGet(ctx context.Context, n int) ([]mockTestInterfaceGetCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsGet)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockTestInterface.Get: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockTestInterface) GetCalled() <-chan mockTestInterfaceGetCall {
+	ch := make(chan mockTestInterfaceGetCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledGet = append(m.calledGet, ch)
+	return ch
+}
+
+func (m *mockTestInterface) recordGet(call mockTestInterfaceGetCall) {
+	m.callsGet = append(m.callsGet, call)
+	for _, ch := range m.calledGet {
+		ch <- call
+	}
+	m.calledGet = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+type mockTestInterfaceResetCall struct{}
+
+func (m *mockTestInterface) ResetCalls() []mockTestInterfaceResetCall {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.callsReset)
+}
+
+func (m *mockTestInterface) ResetCallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.callsReset)
+}
+
+func (m *mockTestInterface) WaitReset(ctx context.Context, n int) ([]mockTestInterfaceResetCall, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.callsReset)
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("mockTestInterface.Reset: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
+}
+
+func (m *mockTestInterface) ResetCalled() <-chan mockTestInterfaceResetCall {
+	ch := make(chan mockTestInterfaceResetCall, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calledReset = append(m.calledReset, ch)
+	return ch
+}
+
+func (m *mockTestInterface) recordReset(call mockTestInterfaceResetCall) {
+	m.callsReset = append(m.callsReset, call)
+	for _, ch := range m.calledReset {
+		ch <- call
+	}
+	m.calledReset = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+
+func newMockTestInterface(opt ...mockTestInterfaceOption) *mockTestInterface {
+	opts := defaultMockTestInterfaceOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	return &mockTestInterface{
+		options: opts,
+	}
+}`, string(out))
+
+		out, err = Exec(md, Opts{Record: true, StructStyle: true, Export: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+type MockTestInterface struct {
+	GetFunc   func(key string, opts ...int) string
+	ResetFunc func()
+
+	mu          sync.RWMutex
+	notify      chan struct{}
+	callsGet    []MockTestInterfaceGetCall
+	calledGet   []chan MockTestInterfaceGetCall
+	callsReset  []MockTestInterfaceResetCall
+	calledReset []chan MockTestInterfaceResetCall
+}
+`)
+		assert.Contains(t, string(out), `
+func (m *MockTestInterface) Get(key string, opts ...int) string {
+	m.mu.Lock()
+	m.recordGet(MockTestInterfaceGetCall{key, opts})
+	m.mu.Unlock()
+	if m.GetFunc != nil {
+		return m.GetFunc(key, opts...)
+	}
+	return ""
+}
+`)
+		assert.Contains(t, string(out), "func (m *MockTestInterface) GetCalls() []MockTestInterfaceGetCall {")
+		assert.Contains(t, string(out), "func (m *MockTestInterface) WaitGet(ctx context.Context, n int) ([]MockTestInterfaceGetCall, error) {")
+		assert.Contains(t, string(out), "func (m *MockTestInterface) ResetCalled() <-chan MockTestInterfaceResetCall {")
+	})
+
+	t.Run("record calls with blank parameters", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Put(_ context.Context, key string, _ []byte)
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Record: true, Expect: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+type mockTestInterfacePutCall struct {
+	P0  context.Context
+	Key string
+	P2  []byte
+}
+`)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Put(p0 context.Context, key string, p2 []byte) {
+	m.mu.Lock()
+	m.recordPut(mockTestInterfacePutCall{p0, key, p2})
+`)
+		assert.Contains(t, string(out), `if c := m.expect.Call("Put", p0, key, p2); c != nil {`)
+	})
+
+	t.Run("parameters named like locals", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Walk(f func(string) error) error
+	Wait(g, inj, tr int, start string)
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{Record: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Walk(p0 func(string) error) error {
+	m.mu.Lock()
+	m.recordWalk(mockTestInterfaceWalkCall{p0})
+	f := m.options.funcWalk
+	m.mu.Unlock()
+	return f(p0)
+}
+`)
+
+		out, err = Exec(md, Opts{Gates: true, Faults: true, Trace: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func (m *mockTestInterface) Wait(p0 int, p1 int, p2 int, p3 string) {")
+
+		// without a lock the options are read directly, and f is left as is
+		out, err = Exec(md, Opts{})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), "func (m *mockTestInterface) Walk(f func(string) error) error {")
+	})
+
 	t.Run("generic interface", func(t *testing.T) {
 		cases := []struct {
 			in  string
@@ -706,7 +1889,7 @@ func (m *mockTestInterface[T, R]) Get() R {
 }
 
 func (m *mockTestInterface[T, R]) Foo(v T) {
-	return
+	m.options.funcFoo(v)
 }
 
 func newMockTestInterface[T any, R ~int](opt ...mockTestInterfaceOption[T, R]) TestInterface[T, R] {
@@ -730,6 +1913,56 @@ func newMockTestInterface[T any, R ~int](opt ...mockTestInterfaceOption[T, R]) T
 			assert.Equal(t, c.out, string(out))
 		}
 	})
+
+	t.Run("generic canned returns", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface[T any, R ~int] interface {
+	Get(v T) (R, error)
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{CannedReturns: true})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func withReturnGet[T any, R ~int](r0 R, r1 error) mockTestInterfaceOption[T, R] {
+	return func(o *mockTestInterfaceOptions[T, R]) {
+		o.funcGet = func(T) (R, error) {
+			return r0, r1
+		}
+	}
+}
+
+func withErrorGet[T any, R ~int](err error) mockTestInterfaceOption[T, R] {
+	return func(o *mockTestInterfaceOptions[T, R]) {
+		o.funcGet = func(T) (R, error) {
+			return *new(R), err
+		}
+	}
+}
+`)
+	})
+
+	t.Run("methods without results call the override", func(t *testing.T) {
+		const in = `
+package test
+type TestInterface interface {
+	Set(v string)
+}
+`
+		md, err := gomock.Parse("", in, "")
+		require.Nil(t, err)
+
+		out, err := Exec(md, Opts{})
+		require.Nil(t, err)
+		assert.Contains(t, string(out), `
+func (m *mockTestInterface) Set(v string) {
+	m.options.funcSet(v)
+}
+`)
+	})
 }
 
 func TestBuildData(t *testing.T) {
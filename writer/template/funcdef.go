@@ -4,12 +4,25 @@ import "strings"
 
 // funcDef represents the information needed to output mocks based on the interface's methods
 type funcDef struct {
-	ServiceName  string // Name of the interface that is being mocked (can be ovverridden by some options)
-	Name         string // Identifier of this function
-	Signature    string // Full parameter list of this function excluding brackets
-	Return       string // Full return parameter list of this function including brackets
-	Args         string // List of function arguments
-	ReturnValues string // List of values that can appear in this function's return statement
+	ServiceName   string     // Name of the interface that is being mocked (can be ovverridden by some options)
+	MockType      string     // Name of the generated mock type
+	TypeArguments string     // Type argument list of the generated mock type
+	Name          string     // Identifier of this function
+	Params        []paramDef // Parameters of this function
+	Results       []paramDef // Results of this function
+	Signature     string     // Full parameter list of this function excluding brackets
+	Types         string     // Parameter types of this function, without names
+	Context       string     // Name of the first context.Context parameter, if any
+	Return        string     // Full return parameter list of this function including brackets
+	Args          string     // List of function arguments
+	ArgNames      string     // List of function arguments without the variadic ellipsis
+	ArgsFormat    string     // List of fmt verbs matching ArgNames
+	ReturnValues  string     // List of values that can appear in this function's return statement
+	ErrorValues   string     // ReturnValues with the trailing error replaced by err, empty if the last result isn't an error
+	DefaultValues string     // ReturnValues with the trailing error replaced by the default error, if any
+	NamedReturn   string     // Return with the results named r0, r1, ...
+	ResultNames   string     // List of the names in NamedReturn
+	ResultsFormat string     // List of fmt verbs matching ResultNames
 }
 
 // Returns a string representation of this funcDef
@@ -18,6 +31,13 @@ func (fd funcDef) String() string {
 	return strings.TrimSpace(s)
 }
 
+// paramDef describes a function parameter or result
+type paramDef struct {
+	Name  string // Parameter name as it appears in the signature
+	Field string // Exported field name that holds the value
+	Type  string // Parameter type, with variadic parameters as slices
+}
+
 type ParamName struct {
 	string
 	IsVararg bool
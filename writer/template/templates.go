@@ -2,20 +2,67 @@ package template
 
 const Notice = `// generated by gomock: do not edit below this line`
 
+// ArgsDirective prefixes the comment that records the arguments a mock file was generated with
+const ArgsDirective = `//gomock:args`
+
 const Options = `
+{{- if .Imports}}
+import (
+	{{range .ImportGroups}}{{range .}}{{.}}
+	{{end}}
+	{{end}}
+)
+{{end}}
 type mock{{.ServiceName}}{{.TypeParamList}} struct {
+	{{- if .Strict}}
+	t testing.TB
+	{{- end}}
+	{{- if .Locked}}
+	mu      sync.RWMutex
+	{{- end}}
 	options mock{{.ServiceName}}Options{{.TypeArguments}}
+	{{- if .Expect}}
+	expect *expect.Controller
+	{{- end}}
+	{{- if .Gates}}
+	{{range .FuncDefs}}gate{{.Name}} *mock{{.ServiceName}}{{.Name}}Gate{{$.TypeArguments}}
+	{{end}}
+	{{- end}}
+	{{- if .VerifyOverrides}}
+	{{range .FuncDefs}}count{{.Name}} int
+	{{end}}
+	{{- end}}
+	{{- if .Record}}{{template "recorderFields" .}}{{end}}
 }
 
 type mock{{.ServiceName}}Options{{.TypeParamList}} struct {
 	{{range .FuncDefs}}func{{.Name}}  func({{.Signature}}) {{.Return}}
 	{{end}}
+	{{- if .Sequence}}
+	sequence *expect.Sequence
+	{{- end}}
+	{{- if .Faults}}
+	injector *fault.Injector
+	{{- end}}
+	{{- if .VerifyOverrides}}
+	{{range .FuncDefs}}override{{.Name}} bool
+	{{end}}
+	{{- end}}
+	{{- if .Trace}}
+	trace func(method, args, results string, d time.Duration)
+	{{- end}}
 }
 
-{{if eq .TypeParamList ""}}
+{{if .Strict}}
+{{else if eq .TypeParamList ""}}
 var defaultMock{{.ServiceName}}Options = mock{{.ServiceName}}Options{
 	{{range .FuncDefs}}func{{.Name}}: func({{.Signature}}) {{.Return}} {
-		return {{.ReturnValues}}
+		{{- if and $.ContextAware .Context .ErrorValues}}
+		if err := {{.Context}}.Err(); err != nil {
+			return {{.ErrorValues}}
+		}
+		{{- end}}
+		return {{.DefaultValues}}
 	},
 	{{end}}
 }
@@ -23,7 +70,12 @@ var defaultMock{{.ServiceName}}Options = mock{{.ServiceName}}Options{
 func newDefaultMock{{.ServiceName}}Options{{.TypeParamList}}() mock{{.ServiceName}}Options{{.TypeArguments}} {
 	return mock{{.ServiceName}}Options{{.TypeArguments}}{
 		{{range .FuncDefs}}func{{.Name}}: func({{.Signature}}) {{.Return}} {
-			return {{.ReturnValues}}
+			{{- if and $.ContextAware .Context .ErrorValues}}
+			if err := {{.Context}}.Err(); err != nil {
+				return {{.ErrorValues}}
+			}
+			{{- end}}
+			return {{.DefaultValues}}
 		},
 		{{end}}
 	}
@@ -33,40 +85,486 @@ func newDefaultMock{{.ServiceName}}Options{{.TypeParamList}}() mock{{.ServiceNam
 type mock{{.ServiceName}}Option{{.TypeParamList}} func(*mock{{.ServiceName}}Options{{.TypeArguments}})
 
 {{range .FuncDefs}}
-func {{if $.Export}}W{{else}}w{{end}}ithFunc{{if $.Disambiguate}}{{.ServiceName}}{{.Name}}{{else}}{{.Name}}{{end}}{{$.TypeParamList}}(f func({{.Signature}}) {{.Return}}) mock{{.ServiceName}}Option{{$.TypeArguments}} {
+func {{$.HelperName "Func" .}}{{$.TypeParamList}}(f func({{.Signature}}) {{.Return}}) mock{{.ServiceName}}Option{{$.TypeArguments}} {
 	return func(o *mock{{.ServiceName}}Options{{$.TypeArguments}}) {
 		o.func{{.Name}} = f
+		{{- if $.VerifyOverrides}}{{template "override" .}}{{end}}
+	}
+}
+{{end}}
+
+{{if .Delegate}}
+func {{.OptionName "Delegate"}}{{.TypeParamList}}(impl {{.InterfaceType}}) mock{{.ServiceName}}Option{{.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{.TypeArguments}}) {
+		{{- range .FuncDefs}}
+		o.func{{.Name}} = impl.{{.Name}}
+		{{- if $.VerifyOverrides}}
+		o.override{{.Name}} = false
+		{{- end}}
+		{{- end}}
+	}
+}
+{{end}}
+
+{{if .Sequence}}
+func {{.OptionName "Recorder"}}{{.TypeParamList}}(seq *expect.Sequence) mock{{.ServiceName}}Option{{.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{.TypeArguments}}) {
+		o.sequence = seq
+	}
+}
+{{end}}
+
+{{if .Trace}}
+func {{.OptionName "Trace"}}{{.TypeParamList}}(t testing.TB) mock{{.ServiceName}}Option{{.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{.TypeArguments}}) {
+		o.trace = func(method, args, results string, d time.Duration) {
+			t.Logf("mock{{.ServiceName}}.%s(%s) -> (%s) in %s", method, args, results, d)
+		}
+	}
+}
+
+func {{.OptionName "TraceLogger"}}{{.TypeParamList}}(l *slog.Logger) mock{{.ServiceName}}Option{{.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{.TypeArguments}}) {
+		o.trace = func(method, args, results string, d time.Duration) {
+			l.Info("mock{{.ServiceName}}."+method, "args", args, "results", results, "duration", d)
+		}
+	}
+}
+{{end}}
+
+{{if .Faults}}
+func {{.OptionName "Injector"}}{{.TypeParamList}}(inj *fault.Injector) mock{{.ServiceName}}Option{{.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{.TypeArguments}}) {
+		o.injector = inj
+	}
+}
+{{end}}
+
+{{if .CannedReturns}}{{range .FuncDefs}}{{if .Results}}
+func {{$.HelperName "Return" .}}{{$.TypeParamList}}{{.NamedReturn}} mock{{.ServiceName}}Option{{$.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{$.TypeArguments}}) {
+		o.func{{.Name}} = func({{.Types}}) {{.Return}} {
+			return {{.ResultNames}}
+		}
+		{{- if $.VerifyOverrides}}{{template "override" .}}{{end}}
+	}
+}
+{{end}}{{if .ErrorValues}}
+func {{$.HelperName "Error" .}}{{$.TypeParamList}}(err error) mock{{.ServiceName}}Option{{$.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{$.TypeArguments}}) {
+		o.func{{.Name}} = func({{.Types}}) {{.Return}} {
+			return {{.ErrorValues}}
+		}
+		{{- if $.VerifyOverrides}}{{template "override" .}}{{end}}
+	}
+}
+{{end}}{{end}}{{end}}
+
+{{if and .SequencedReturns .HasResults}}
+// mock{{.ServiceName}}Exhausted selects what a method returns once its sequence of return values is exhausted
+type mock{{.ServiceName}}Exhausted int
+
+const (
+	mock{{.ServiceName}}RepeatLast mock{{.ServiceName}}Exhausted = iota // return the last values again
+	mock{{.ServiceName}}ReturnZero // return zero values
+	mock{{.ServiceName}}Fail // panic
+)
+{{end}}
+
+{{range .FuncDefs}}{{if .Results}}
+{{- if or $.SequencedReturns $.Expect $.Gates}}
+type mock{{.ServiceName}}{{.Name}}Result{{$.TypeParamList}} struct {
+	{{range .Results}}{{.Field}} {{.Type}}
+	{{end}}
+}
+{{end}}
+{{- if $.SequencedReturns}}
+func {{$.HelperName "Returns" .}}{{$.TypeParamList}}(results []mock{{.ServiceName}}{{.Name}}Result{{$.TypeArguments}}, exhausted mock{{.ServiceName}}Exhausted) mock{{.ServiceName}}Option{{$.TypeArguments}} {
+	return func(o *mock{{.ServiceName}}Options{{$.TypeArguments}}) {
+		var (
+			mu sync.Mutex
+			n  int
+		)
+		o.func{{.Name}} = func({{.Types}}) {{.Return}} {
+			mu.Lock()
+			defer mu.Unlock()
+			var r mock{{.ServiceName}}{{.Name}}Result{{$.TypeArguments}}
+			switch {
+			case n < len(results):
+				r = results[n]
+				n++
+			case exhausted == mock{{.ServiceName}}Fail:
+				panic("mock{{.ServiceName}}.{{.Name}}: no more return values")
+			case exhausted == mock{{.ServiceName}}RepeatLast && len(results) > 0:
+				r = results[len(results)-1]
+			}
+			return {{range $i, $r := .Results}}{{if $i}}, {{end}}r.{{$r.Field}}{{end}}
+		}
+		{{- if $.VerifyOverrides}}{{template "override" .}}{{end}}
 	}
 }
 {{end}}
+{{- end}}{{end}}
 
 {{range .FuncDefs}}
+func (m *mock{{.ServiceName}}{{$.TypeArguments}}) {{.Name}}({{.Signature}}) {{if and $.Trace .Results}}{{.NamedReturn}}{{else}}{{.Return}}{{end}} {
+	{{- $f := "f"}}{{$inj := "inj"}}{{$tr := "tr"}}
+	{{- if $.Locked}}
+	{{- $lock := "RLock"}}{{$unlock := "RUnlock"}}
+	{{- if or $.Record $.VerifyOverrides}}{{$lock = "Lock"}}{{$unlock = "Unlock"}}{{end}}
+	m.mu.{{$lock}}()
+	{{- if $.Record}}
+	m.record{{.Name}}({{.MockType}}{{.Name}}Call{{.TypeArguments}}{ {{- .ArgNames -}} })
+	{{- end}}
+	{{- if $.VerifyOverrides}}
+	m.count{{.Name}}++
+	{{- end}}
+	f := m.options.func{{.Name}}
+	{{- if $.Gates}}
+	g := m.gate{{.Name}}
+	{{- end}}
+	{{- if $.Faults}}
+	inj := m.options.injector
+	{{- end}}
+	{{- if $.Trace}}
+	tr := m.options.trace
+	{{- end}}
+	{{- if $.Sequence}}{{template "sequence" .}}{{end}}
+	m.mu.{{$unlock}}()
+	{{- else}}
+	{{- /* the options don't change after construction, they are read without locking */}}
+	{{- $f = printf "m.options.func%s" .Name}}{{$inj = "m.options.injector"}}{{$tr = "m.options.trace"}}
+	{{- if $.Sequence}}{{template "sequence" .}}{{end}}
+	{{- end}}
+	{{- if $.Trace}}
+	if {{$tr}} != nil {
+		defer func(start time.Time) {
+			{{$tr}}("{{.Name}}", {{if .ArgNames}}fmt.Sprintf("{{.ArgsFormat}}", {{.ArgNames}}){{else}}""{{end}}, {{if .Results}}fmt.Sprintf("{{.ResultsFormat}}", {{.ResultNames}}){{else}}""{{end}}, time.Since(start))
+		}(time.Now())
+	}
+	{{- end}}
+	{{- if $.Gates}}
+	if g != nil {
+		{{- if .Context}}
+		select {
+		case <-g.released:
+		case <-{{.Context}}.Done():
+			{{- if .ErrorValues}}
+			err := {{.Context}}.Err()
+			return {{.ErrorValues}}
+			{{- else}}
+			return {{.DefaultValues}}
+			{{- end}}
+		}
+		{{- else}}
+		<-g.released
+		{{- end}}
+		{{- if .Results}}
+		if r := g.result; r != nil {
+			return {{range $i, $r := .Results}}{{if $i}}, {{end}}r.{{$r.Field}}{{end}}
+		}
+		{{- end}}
+	}
+	{{- end}}
+	{{- if $.Faults}}
+	{{- if .ErrorValues}}
+	if err := {{$inj}}.{{template "injectCall" .}}; err != nil {
+		return {{.ErrorValues}}
+	}
+	{{- else}}
+	{{$inj}}.{{template "injectCall" .}}
+	{{- end}}
+	{{- end}}
+	{{- if $.Expect}}
+	if c := m.expect.Call("{{.Name}}"{{if .ArgNames}}, {{.ArgNames}}{{end}}); c != nil {
+		{{- if .Results}}
+		r, _ := c.Result().(mock{{.ServiceName}}{{.Name}}Result{{$.TypeArguments}})
+		return {{range $i, $r := .Results}}{{if $i}}, {{end}}r.{{$r.Field}}{{end}}
+		{{- else}}
+		return
+		{{- end}}
+	}
+	{{- end}}
+	{{- if $.Strict}}
+	if {{$f}} == nil {
+		m.t.Helper()
+		m.t.Fatalf("unexpected call to mock{{.ServiceName}}.{{.Name}}({{.ArgsFormat}})"{{if .ArgNames}}, {{.ArgNames}}{{end}})
+	}
+	{{- end}}
+	{{if .Return}}return {{end}}{{$f}}({{.Args}})
+}
+{{end}}
+
+{{range .Stubs}}
 func (m *mock{{.ServiceName}}{{$.TypeArguments}}) {{.Name}}({{.Signature}}) {{.Return}} {
-	return {{if .Return}}m.options.func{{.Name}}({{.Args}}){{end}}
+	panic("mock{{.ServiceName}}.{{.Name}}: not mocked")
+}
+{{end}}
+
+{{if .Record}}{{template "calls" .}}{{end}}
+
+{{if .Gates}}{{range .FuncDefs}}
+type mock{{.ServiceName}}{{.Name}}Gate{{$.TypeParamList}} struct {
+	released chan struct{}
+	once     sync.Once
+	{{- if .Results}}
+	result   *mock{{.ServiceName}}{{.Name}}Result{{$.TypeArguments}}
+	{{- end}}
+}
+
+func (m *mock{{.ServiceName}}{{$.TypeArguments}}) Gate{{.Name}}() *mock{{.ServiceName}}{{.Name}}Gate{{$.TypeArguments}} {
+	g := &mock{{.ServiceName}}{{.Name}}Gate{{$.TypeArguments}}{released: make(chan struct{})}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gate{{.Name}} = g
+	return g
+}
+
+func (g *mock{{.ServiceName}}{{.Name}}Gate{{$.TypeArguments}}) Release() {
+	g.once.Do(func() {
+		close(g.released)
+	})
+}
+{{if .Results}}
+func (g *mock{{.ServiceName}}{{.Name}}Gate{{$.TypeArguments}}) ReleaseWith({{range $i, $r := .Results}}{{if $i}}, {{end}}{{$r.Name}} {{$r.Type}}{{end}}) {
+	g.once.Do(func() {
+		g.result = &mock{{.ServiceName}}{{.Name}}Result{{$.TypeArguments}}{ {{- range $i, $r := .Results}}{{if $i}}, {{end}}{{$r.Name}}{{end -}} }
+		close(g.released)
+	})
+}
+{{end}}{{end}}{{end}}
+
+{{if .VerifyOverrides}}
+func (m *mock{{.ServiceName}}{{$.TypeArguments}}) AssertOverridesCalled(t testing.TB) bool {
+	t.Helper()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ok := true
+	{{- range .FuncDefs}}
+	if m.options.override{{.Name}} && m.count{{.Name}} == 0 {
+		t.Errorf("mock{{.ServiceName}}.{{.Name}} is overridden but was never called")
+		ok = false
+	}
+	{{- end}}
+	return ok
+}
+{{end}}
+
+{{if .Reset}}
+func (m *mock{{.ServiceName}}{{$.TypeArguments}}) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	{{- if or .Sequence .Faults .Trace}}
+	opts := {{template "defaultOptions" .}}
+	{{- if .Sequence}}
+	opts.sequence = m.options.sequence
+	{{- end}}
+	{{- if .Faults}}
+	opts.injector = m.options.injector
+	{{- end}}
+	{{- if .Trace}}
+	opts.trace = m.options.trace
+	{{- end}}
+	m.options = opts
+	{{- else}}
+	m.options = {{template "defaultOptions" .}}
+	{{- end}}
+	{{- if .Expect}}
+	m.expect.Reset()
+	{{- end}}
+	{{- if .Record}}{{range .FuncDefs}}
+	m.calls{{.Name}} = nil
+	{{- end}}
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+	{{- end}}
+	{{- if .Gates}}{{range .FuncDefs}}
+	m.gate{{.Name}} = nil
+	{{- end}}{{end}}
+	{{- if .VerifyOverrides}}{{range .FuncDefs}}
+	m.count{{.Name}} = 0
+	{{- end}}{{end}}
+}
+
+func (m *mock{{.ServiceName}}{{$.TypeArguments}}) Apply(opt ...mock{{.ServiceName}}Option{{.TypeArguments}}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, o := range opt {
+		o(&m.options)
+	}
+}
+{{end}}
+
+{{define "defaultOptions" -}}
+	{{if .Strict}}mock{{.ServiceName}}Options{{.TypeArguments}}{}{{else if eq .TypeParamList ""}}defaultMock{{.ServiceName}}Options{{else}}newDefaultMock{{.ServiceName}}Options{{.TypeArguments}}(){{end}}
+{{- end}}
+
+{{define "override"}}
+		o.override{{.Name}} = true
+{{- end}}
+
+{{define "injectCall" -}}
+	{{if .Context}}InjectContext({{.Context}}, {{else}}Inject({{end}}"{{.Name}}", {{if .ErrorValues}}true{{else}}false{{end}})
+{{- end}}
+
+{{define "sequence"}}
+	m.options.sequence.Add("{{.ServiceName}}.{{.Name}}"{{if .ArgNames}}, {{.ArgNames}}{{end}})
+{{- end}}
+
+{{if .Expect}}{{range .FuncDefs}}
+type mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeParamList}} struct {
+	call *expect.Call
+}
+
+func (m *mock{{.ServiceName}}{{$.TypeArguments}}) Expect{{.Name}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}} any{{end}}) *mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}} {
+	return &mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}}{m.expect.Expect("{{.Name}}"{{if .ArgNames}}, {{.ArgNames}}{{end}})}
+}
+{{if .Results}}
+func (e *mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}}) Return({{range $i, $r := .Results}}{{if $i}}, {{end}}{{$r.Name}} {{$r.Type}}{{end}}) *mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}} {
+	e.call.SetResult(mock{{.ServiceName}}{{.Name}}Result{{$.TypeArguments}}{ {{- range $i, $r := .Results}}{{if $i}}, {{end}}{{$r.Name}}{{end -}} })
+	return e
 }
 {{end}}
+func (e *mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}}) Times(n int) *mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}} {
+	e.call.Times(n)
+	return e
+}
+
+func (e *mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}}) AnyTimes() *mock{{.ServiceName}}{{.Name}}Expectation{{$.TypeArguments}} {
+	e.call.AnyTimes()
+	return e
+}
+{{end}}{{end}}
 
-func {{if .Export}}N{{else}}n{{end}}ewMock{{.ServiceName}}{{.TypeParamList}}(opt ...mock{{.ServiceName}}Option{{.TypeArguments}}) {{if .Qualify}}{{.Package}}.{{end}}{{if and .Qualify .PrefixPackage }}{{.InterfaceName}}{{else}}{{.ServiceName}}{{end}}{{.TypeArguments}} {
-	opts := {{if eq .TypeParamList ""}}defaultMock{{.ServiceName}}Options{{else}}newDefaultMock{{.ServiceName}}Options{{.TypeArguments}}(){{end}}
+func {{if .Export}}N{{else}}n{{end}}ewMock{{.ServiceName}}{{.TypeParamList}}({{if or .Strict .Expect}}t testing.TB, {{end}}opt ...mock{{.ServiceName}}Option{{.TypeArguments}}) {{if .ReturnsMock}}*mock{{.ServiceName}}{{else}}{{if .Qualify}}{{.Package}}.{{end}}{{if and .Qualify .PrefixPackage }}{{.InterfaceName}}{{else}}{{.ServiceName}}{{end}}{{end}}{{.TypeArguments}} {
+	opts := {{template "defaultOptions" .}}
 	for _, o := range opt {
 		o(&opts)
 	}
-	return &mock{{.ServiceName}}{{.TypeArguments}}{
+	{{if and .VerifyOverrides (or .Strict .Expect)}}m :={{else}}return{{end}} &mock{{.ServiceName}}{{.TypeArguments}}{
+		{{- if .Strict}}
+		t:       t,
+		{{- end}}
 		options: opts,
+		{{- if .Expect}}
+		expect: expect.NewController(t),
+		{{- end}}
 	}
+	{{- if and .VerifyOverrides (or .Strict .Expect)}}
+	t.Cleanup(func() {
+		m.AssertOverridesCalled(t)
+	})
+	return m
+	{{- end}}
 }`
 
 const Struct = `
+{{- if .Imports}}
+import (
+	{{range .ImportGroups}}{{range .}}{{.}}
+	{{end}}
+	{{end}}
+)
+{{end}}
 type {{if .Export}}M{{else}}m{{end}}ock{{.ServiceName}}{{.TypeParamList}} struct {
 	{{range .FuncDefs}}{{.Name}}Func  func({{.Signature}}) {{.Return}}
 	{{end}}
+	{{- if .Record}}
+	mu sync.RWMutex
+	{{- template "recorderFields" .}}{{end}}
 }
 
 {{range .FuncDefs}}
 func (m *{{if $.Export}}M{{else}}m{{end}}ock{{.ServiceName}}{{$.TypeArguments}}) {{.Name}}({{.Signature}}) {{.Return}} {
+	{{- if $.Record}}{{template "record" .}}{{end}}
 	if m.{{.Name}}Func != nil {
 		{{if .Return}}return m.{{.Name}}Func({{.Args}}){{else}}m.{{.Name}}Func({{.Args}}){{end}}
 	}
-	{{if .Return}}return {{.ReturnValues}}{{end -}}
+	{{if .Return}}return {{.DefaultValues}}{{end -}}
+}
+{{end}}
+{{- range .Stubs}}
+func (m *{{if $.Export}}M{{else}}m{{end}}ock{{.ServiceName}}{{$.TypeArguments}}) {{.Name}}({{.Signature}}) {{.Return}} {
+	panic("{{if $.Export}}M{{else}}m{{end}}ock{{.ServiceName}}.{{.Name}}: not mocked")
+}
+{{end}}
+{{- if .Record}}{{template "calls" .}}{{end}}`
+
+// Recorder defines the templates that record the calls to the mock methods.
+const Recorder = `
+{{define "recorderFields"}}
+	notify chan struct{}
+	{{range .FuncDefs}}calls{{.Name}} []{{.MockType}}{{.Name}}Call{{.TypeArguments}}
+	called{{.Name}} []chan {{.MockType}}{{.Name}}Call{{.TypeArguments}}
+	{{end}}
+{{- end}}
+
+{{define "record"}}
+	m.mu.Lock()
+	m.record{{.Name}}({{.MockType}}{{.Name}}Call{{.TypeArguments}}{ {{- .ArgNames -}} })
+	m.mu.Unlock()
+{{- end}}
+
+{{define "calls"}}
+{{- range .FuncDefs}}
+type {{.MockType}}{{.Name}}Call{{$.TypeParamList}} {{if .Params}}struct {
+	{{range .Params}}{{.Field}} {{.Type}}
+	{{end}}
+}{{else}}struct{}{{end}}
+
+func (m *{{.MockType}}{{.TypeArguments}}) {{.Name}}Calls() []{{.MockType}}{{.Name}}Call{{.TypeArguments}} {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.calls{{.Name}})
+}
+
+func (m *{{.MockType}}{{.TypeArguments}}) {{.Name}}CallCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.calls{{.Name}})
+}
+
+func (m *{{.MockType}}{{.TypeArguments}}) Wait{{.Name}}(ctx context.Context, n int) ([]{{.MockType}}{{.Name}}Call{{.TypeArguments}}, error) {
+	for {
+		m.mu.Lock()
+		calls := slices.Clone(m.calls{{.Name}})
+		if m.notify == nil {
+			m.notify = make(chan struct{})
+		}
+		notify := m.notify
+		m.mu.Unlock()
+
+		if len(calls) >= n {
+			return calls[:n], nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return calls, fmt.Errorf("{{.MockType}}.{{.Name}}: got %d of %d calls %+v: %w", len(calls), n, calls, ctx.Err())
+		}
+	}
 }
-{{end}}`
+
+func (m *{{.MockType}}{{.TypeArguments}}) {{.Name}}Called() <-chan {{.MockType}}{{.Name}}Call{{.TypeArguments}} {
+	ch := make(chan {{.MockType}}{{.Name}}Call{{.TypeArguments}}, 1)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.called{{.Name}} = append(m.called{{.Name}}, ch)
+	return ch
+}
+
+func (m *{{.MockType}}{{.TypeArguments}}) record{{.Name}}(call {{.MockType}}{{.Name}}Call{{.TypeArguments}}) {
+	m.calls{{.Name}} = append(m.calls{{.Name}}, call)
+	for _, ch := range m.called{{.Name}} {
+		ch <- call
+	}
+	m.called{{.Name}} = nil
+	if m.notify != nil {
+		close(m.notify)
+		m.notify = nil
+	}
+}
+{{end}}
+{{- end}}`